@@ -2,38 +2,67 @@ package testastic
 
 import (
 	"cmp"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strings"
-	"testing"
+	"time"
 )
 
 // fail reports an assertion failure with expected and actual values.
-func fail(tb testing.TB, name, expected, actual string) {
+func fail(tb Reporter, name, expected, actual string, msgAndArgs []any) {
 	tb.Helper()
-	tb.Errorf(
+	failf(tb, msgAndArgs,
 		"testastic: assertion failed\n\n  %s\n    expected: %s\n    actual:   %s",
 		name, red(expected), green(actual),
 	)
 }
 
+// failf reports an assertion failure, preceded by the custom message of the
+// assertion if one was given.
+func failf(tb Reporter, msgAndArgs []any, format string, args ...any) {
+	tb.Helper()
+	tb.Errorf("%s%s", formatMessage(msgAndArgs), fmt.Sprintf(format, args...))
+}
+
+// formatMessage formats the optional msgAndArgs of an assertion as a line to put
+// before the failure. The first element is a message, or a format string for the
+// remaining elements. It returns "" if no message was given.
+func formatMessage(msgAndArgs []any) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	}
+
+	format, ok := msgAndArgs[0].(string)
+	if !ok {
+		return fmt.Sprint(msgAndArgs...) + "\n"
+	}
+
+	if len(msgAndArgs) == 1 {
+		return format + "\n"
+	}
+
+	return fmt.Sprintf(format, msgAndArgs[1:]...) + "\n"
+}
+
 // Equal asserts that expected and actual are equal.
-func Equal[T comparable](tb testing.TB, expected, actual T) {
+func Equal[T comparable](tb Reporter, expected, actual T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if expected != actual {
-		fail(tb, "Equal", formatVal(expected), formatVal(actual))
+		fail(tb, "Equal", formatVal(expected), formatVal(actual), msgAndArgs)
 	}
 }
 
 // NotEqual asserts that expected and actual are not equal.
-func NotEqual[T comparable](tb testing.TB, unexpected, actual T) {
+func NotEqual[T comparable](tb Reporter, unexpected, actual T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if unexpected == actual {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  NotEqual\n    unexpected: %s\n    actual:     %s",
 			red(formatVal(unexpected)), green(formatVal(actual)),
 		)
@@ -41,70 +70,265 @@ func NotEqual[T comparable](tb testing.TB, unexpected, actual T) {
 }
 
 // DeepEqual asserts that expected and actual are deeply equal using reflect.DeepEqual.
-func DeepEqual[T any](tb testing.TB, expected, actual T) {
+func DeepEqual[T any](tb Reporter, expected, actual T, msgAndArgs ...any) {
+	tb.Helper()
+
+	if reflect.DeepEqual(expected, actual) {
+		return
+	}
+
+	diff, ok := formatDeepEqualDiff(expected, actual)
+	if !ok {
+		fail(tb, "DeepEqual", formatVal(expected), formatVal(actual), msgAndArgs)
+
+		return
+	}
+
+	failf(tb, msgAndArgs, "testastic: assertion failed\n\n  DeepEqual\n%s", diff)
+}
+
+// formatDeepEqualDiff returns a line-level diff of expected and actual rendered as
+// indented JSON, or with %+v if either side cannot be marshaled or both render the
+// same, e.g. because they differ only in unexported fields. It returns false if the
+// renderings are still identical, so a diff would show no change.
+func formatDeepEqualDiff(expected, actual any) (string, bool) {
+	expJSON, expErr := json.MarshalIndent(expected, "", "  ")
+	actJSON, actErr := json.MarshalIndent(actual, "", "  ")
+
+	exp, act := string(expJSON), string(actJSON)
+	if expErr != nil || actErr != nil || exp == act {
+		exp, act = fmt.Sprintf("%+v", expected), fmt.Sprintf("%+v", actual)
+	}
+
+	if exp == act {
+		return "", false
+	}
+
+	var sb strings.Builder
+
+	for _, line := range computeDiff(strings.Split(exp, "\n"), strings.Split(act, "\n")) {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), true
+}
+
+// EqualDeref asserts that expected and actual point to equal values. It fails if
+// either pointer is nil.
+func EqualDeref[T comparable](tb Reporter, expected, actual *T, msgAndArgs ...any) {
+	tb.Helper()
+
+	if expected == nil || actual == nil {
+		fail(tb, "EqualDeref", formatPointerVal(expected), formatPointerVal(actual), msgAndArgs)
+
+		return
+	}
+
+	if *expected != *actual {
+		fail(tb, "EqualDeref", formatVal(*expected), formatVal(*actual), msgAndArgs)
+	}
+}
+
+// EqualValue asserts that expected and actual are deeply equal after dereferencing
+// pointers on both sides, so a *int pointing to 5 equals 5. A nil pointer equals only
+// nil or another nil pointer.
+func EqualValue(tb Reporter, expected, actual any, msgAndArgs ...any) {
+	tb.Helper()
+
+	exp, act := deref(expected), deref(actual)
+	if !reflect.DeepEqual(exp, act) {
+		fail(tb, "EqualValue", formatVal(exp), formatVal(act), msgAndArgs)
+	}
+}
+
+// deref follows pointers until it reaches a non-pointer value or a nil pointer,
+// which is returned as untyped nil.
+func deref(v any) any {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	return rv.Interface()
+}
+
+// formatPointerVal formats the value a pointer points to, or nil.
+func formatPointerVal[T any](p *T) string {
+	if p == nil {
+		return "nil"
+	}
+
+	return formatVal(*p)
+}
+
+// IsType asserts that actual has the same dynamic type as expected, whose value is
+// ignored. An untyped nil has no type: it only matches another untyped nil, and a
+// nil pointer must be passed as a typed value such as (*User)(nil).
+//
+// Example:
+//
+//	testastic.IsType(t, map[string]any{}, decoded)
+func IsType(tb Reporter, expected, actual any, msgAndArgs ...any) {
 	tb.Helper()
 
-	if !reflect.DeepEqual(expected, actual) {
-		fail(tb, "DeepEqual", formatVal(expected), formatVal(actual))
+	if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+		fail(tb, "IsType", typeName(expected), typeName(actual), msgAndArgs)
 	}
 }
 
+// typeName returns the dynamic type of v, or "nil (no type)" for an untyped nil.
+func typeName(v any) string {
+	if v == nil {
+		return "nil (no type)"
+	}
+
+	return reflect.TypeOf(v).String()
+}
+
+// Same asserts that expected and actual point to the same object. Both must be
+// pointers, slices, maps, channels or funcs of the same type. Unlike Equal and
+// DeepEqual, two different pointers to equal values fail.
+func Same(tb Reporter, expected, actual any, msgAndArgs ...any) {
+	tb.Helper()
+
+	same, ok := samePointer(expected, actual)
+	if !ok {
+		failNotPointers(tb, "Same", expected, actual, msgAndArgs)
+
+		return
+	}
+
+	if !same {
+		fail(tb, "Same", formatPointer(expected), formatPointer(actual), msgAndArgs)
+	}
+}
+
+// NotSame asserts that expected and actual do not point to the same object.
+// Both must be pointers, slices, maps, channels or funcs.
+func NotSame(tb Reporter, unexpected, actual any, msgAndArgs ...any) {
+	tb.Helper()
+
+	same, ok := samePointer(unexpected, actual)
+	if !ok {
+		failNotPointers(tb, "NotSame", unexpected, actual, msgAndArgs)
+
+		return
+	}
+
+	if same {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  NotSame\n    unexpected: %s\n    actual:     %s",
+			red(formatPointer(unexpected)), green(formatPointer(actual)),
+		)
+	}
+}
+
+// samePointer reports whether a and b have the same type and pointer. The second
+// return value is false if either is not pointer-like.
+func samePointer(a, b any) (bool, bool) {
+	if !isPointerLike(a) || !isPointerLike(b) {
+		return false, false
+	}
+
+	return reflect.TypeOf(a) == reflect.TypeOf(b) &&
+		reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer(), true
+}
+
+// isPointerLike reports whether v is a kind that reflect.Value.Pointer supports.
+func isPointerLike(v any) bool {
+	if v == nil {
+		return false
+	}
+
+	//nolint:exhaustive // Only pointer-like kinds have identity.
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatPointer formats a pointer-like value with its type and address.
+func formatPointer(v any) string {
+	return fmt.Sprintf("%T(%#x)", v, reflect.ValueOf(v).Pointer())
+}
+
+// failNotPointers reports that Same or NotSame was called with values that have no identity.
+func failNotPointers(tb Reporter, name string, a, b any, msgAndArgs []any) {
+	tb.Helper()
+	failf(tb, msgAndArgs,
+		"testastic: assertion failed\n\n  %s\n    error: expected pointer-like values, got %T and %T",
+		name, a, b,
+	)
+}
+
 // Nil asserts that value is nil.
-func Nil(tb testing.TB, value any) {
+func Nil(tb Reporter, value any, msgAndArgs ...any) {
 	tb.Helper()
 
 	if !isNil(value) {
-		fail(tb, "Nil", "nil", formatVal(value))
+		fail(tb, "Nil", "nil", formatVal(value), msgAndArgs)
 	}
 }
 
 // NotNil asserts that value is not nil.
-func NotNil(tb testing.TB, value any) {
+func NotNil(tb Reporter, value any, msgAndArgs ...any) {
 	tb.Helper()
 
 	if isNil(value) {
-		fail(tb, "NotNil", "not nil", "nil")
+		fail(tb, "NotNil", "not nil", "nil", msgAndArgs)
 	}
 }
 
 // True asserts that value is true.
-func True(tb testing.TB, value bool) {
+func True(tb Reporter, value bool, msgAndArgs ...any) {
 	tb.Helper()
 
 	if !value {
-		fail(tb, "True", "true", "false")
+		fail(tb, "True", "true", "false", msgAndArgs)
 	}
 }
 
 // False asserts that value is false.
-func False(tb testing.TB, value bool) {
+func False(tb Reporter, value bool, msgAndArgs ...any) {
 	tb.Helper()
 
 	if value {
-		fail(tb, "False", "false", "true")
+		fail(tb, "False", "false", "true", msgAndArgs)
 	}
 }
 
 // NoError asserts that err is nil.
-func NoError(tb testing.TB, err error) {
+func NoError(tb Reporter, err error, msgAndArgs ...any) {
 	tb.Helper()
 
 	if err != nil {
-		fail(tb, "NoError", "no error", err.Error())
+		fail(tb, "NoError", "no error", err.Error(), msgAndArgs)
 	}
 }
 
 // Error asserts that err is not nil.
-func Error(tb testing.TB, err error) {
+func Error(tb Reporter, err error, msgAndArgs ...any) {
 	tb.Helper()
 
 	if err == nil {
-		fail(tb, "Error", "an error", "nil")
+		fail(tb, "Error", "an error", "nil", msgAndArgs)
 	}
 }
 
 // ErrorIs asserts that err matches target using errors.Is.
-func ErrorIs(tb testing.TB, err, target error) {
+func ErrorIs(tb Reporter, err, target error, msgAndArgs ...any) {
 	tb.Helper()
 
 	if !errors.Is(err, target) {
@@ -113,134 +337,290 @@ func ErrorIs(tb testing.TB, err, target error) {
 			errStr = err.Error()
 		}
 
-		fail(tb, "ErrorIs", target.Error(), errStr)
+		fail(tb, "ErrorIs", target.Error(), errStr, msgAndArgs)
 	}
 }
 
+// ErrorAs asserts that err matches the error type E using errors.As and returns
+// the typed error, so its fields can be asserted on directly. It fails fatally if
+// no error in the chain matches.
+//
+// Example:
+//
+//	pathErr := testastic.ErrorAs[*fs.PathError](t, err)
+//	testastic.Equal(t, "open", pathErr.Op)
+func ErrorAs[E error](tb Reporter, err error, msgAndArgs ...any) E {
+	tb.Helper()
+
+	var target E
+	if errors.As(err, &target) {
+		return target
+	}
+
+	errStr := "nil"
+	if err != nil {
+		errStr = err.Error()
+	}
+
+	tb.Fatalf(
+		"%stestastic: assertion failed\n\n  ErrorAs\n    expected: %s\n    actual:   %s",
+		formatMessage(msgAndArgs), red("error of type "+reflect.TypeFor[E]().String()), green(errStr),
+	)
+
+	return target
+}
+
 // ErrorContains asserts that err contains the given substring.
-func ErrorContains(tb testing.TB, err error, substring string) {
+func ErrorContains(tb Reporter, err error, substring string, msgAndArgs ...any) {
 	tb.Helper()
 
 	wantMsg := "error containing " + fmt.Sprintf("%q", substring)
 
 	if err == nil {
-		fail(tb, "ErrorContains", wantMsg, "nil")
+		fail(tb, "ErrorContains", wantMsg, "nil", msgAndArgs)
 
 		return
 	}
 
 	if !strings.Contains(err.Error(), substring) {
-		fail(tb, "ErrorContains", wantMsg, err.Error())
+		fail(tb, "ErrorContains", wantMsg, err.Error(), msgAndArgs)
 	}
 }
 
 // failCmp reports a comparison assertion failure.
-func failCmp(tb testing.TB, name, expectOp, actualOp, a, b string) {
+func failCmp(tb Reporter, name, expectOp, actualOp, a, b string, msgAndArgs []any) {
 	tb.Helper()
-	tb.Errorf(
+	failf(tb, msgAndArgs,
 		"testastic: assertion failed\n\n  %s\n    expected: %s %s %s\n    actual:   %s %s %s",
 		name, red(a), expectOp, red(b), green(a), actualOp, green(b),
 	)
 }
 
 // Greater asserts that a > b.
-func Greater[T cmp.Ordered](tb testing.TB, a, b T) {
+func Greater[T cmp.Ordered](tb Reporter, a, b T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if a <= b {
-		failCmp(tb, "Greater", ">", "<=", formatVal(a), formatVal(b))
+		failCmp(tb, "Greater", ">", "<=", formatVal(a), formatVal(b), msgAndArgs)
 	}
 }
 
 // GreaterOrEqual asserts that a >= b.
-func GreaterOrEqual[T cmp.Ordered](tb testing.TB, a, b T) {
+func GreaterOrEqual[T cmp.Ordered](tb Reporter, a, b T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if a < b {
-		failCmp(tb, "GreaterOrEqual", ">=", "<", formatVal(a), formatVal(b))
+		failCmp(tb, "GreaterOrEqual", ">=", "<", formatVal(a), formatVal(b), msgAndArgs)
 	}
 }
 
 // Less asserts that a < b.
-func Less[T cmp.Ordered](tb testing.TB, a, b T) {
+func Less[T cmp.Ordered](tb Reporter, a, b T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if a >= b {
-		failCmp(tb, "Less", "<", ">=", formatVal(a), formatVal(b))
+		failCmp(tb, "Less", "<", ">=", formatVal(a), formatVal(b), msgAndArgs)
 	}
 }
 
 // LessOrEqual asserts that a <= b.
-func LessOrEqual[T cmp.Ordered](tb testing.TB, a, b T) {
+func LessOrEqual[T cmp.Ordered](tb Reporter, a, b T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if a > b {
-		failCmp(tb, "LessOrEqual", "<=", ">", formatVal(a), formatVal(b))
+		failCmp(tb, "LessOrEqual", "<=", ">", formatVal(a), formatVal(b), msgAndArgs)
 	}
 }
 
 // Between asserts that minVal <= value <= maxVal.
-func Between[T cmp.Ordered](tb testing.TB, value, minVal, maxVal T) {
+func Between[T cmp.Ordered](tb Reporter, value, minVal, maxVal T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if value < minVal || value > maxVal {
 		expected := formatVal(minVal) + " <= value <= " + formatVal(maxVal)
-		fail(tb, "Between", expected, formatVal(value))
+		fail(tb, "Between", expected, formatVal(value), msgAndArgs)
+	}
+}
+
+// NotBetween asserts that value < minVal or value > maxVal.
+func NotBetween[T cmp.Ordered](tb Reporter, value, minVal, maxVal T, msgAndArgs ...any) {
+	tb.Helper()
+
+	if value >= minVal && value <= maxVal {
+		expected := "value < " + formatVal(minVal) + " or value > " + formatVal(maxVal)
+		fail(tb, "NotBetween", expected, formatVal(value), msgAndArgs)
+	}
+}
+
+// EqualWithin asserts that expected and actual differ by at most delta.
+func EqualWithin(tb Reporter, expected, actual, delta float64, msgAndArgs ...any) {
+	tb.Helper()
+
+	diff := math.Abs(expected - actual)
+	if !(diff <= delta) {
+		fail(tb, "EqualWithin",
+			fmt.Sprintf("%v (±%v)", expected, delta),
+			fmt.Sprintf("%v (difference: %v)", actual, diff), msgAndArgs)
+	}
+}
+
+// InEpsilon asserts that the relative error |expected-actual|/|expected| is at most epsilon.
+// If expected is 0, the relative error is undefined and actual must be exactly 0.
+func InEpsilon(tb Reporter, expected, actual, epsilon float64, msgAndArgs ...any) {
+	tb.Helper()
+
+	if expected == 0 {
+		if actual != 0 {
+			fail(tb, "InEpsilon",
+				"0 (relative error undefined for expected 0)",
+				formatVal(actual), msgAndArgs)
+		}
+
+		return
+	}
+
+	relErr := math.Abs(expected-actual) / math.Abs(expected)
+	if !(relErr <= epsilon) {
+		fail(tb, "InEpsilon",
+			fmt.Sprintf("%v (relative error <= %v)", expected, epsilon),
+			fmt.Sprintf("%v (relative error: %v)", actual, relErr), msgAndArgs)
+	}
+}
+
+// Before asserts that time a is before time b.
+func Before(tb Reporter, a, b time.Time, msgAndArgs ...any) {
+	tb.Helper()
+
+	if !a.Before(b) {
+		failCmp(tb, "Before", "before", "not before", formatTime(a), formatTime(b), msgAndArgs)
+	}
+}
+
+// After asserts that time a is after time b.
+func After(tb Reporter, a, b time.Time, msgAndArgs ...any) {
+	tb.Helper()
+
+	if !a.After(b) {
+		failCmp(tb, "After", "after", "not after", formatTime(a), formatTime(b), msgAndArgs)
+	}
+}
+
+// TimeEqual asserts that times expected and actual differ by at most tolerance,
+// regardless of their locations.
+func TimeEqual(tb Reporter, expected, actual time.Time, tolerance time.Duration, msgAndArgs ...any) {
+	tb.Helper()
+
+	diff := actual.Sub(expected).Abs()
+	if diff > tolerance {
+		fail(tb, "TimeEqual",
+			fmt.Sprintf("%s (±%s)", formatTime(expected), tolerance),
+			fmt.Sprintf("%s (difference: %s)", formatTime(actual), diff), msgAndArgs)
+	}
+}
+
+// formatTime formats a time for failure output in RFC 3339 with fractional seconds.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// Eventually asserts that condition returns true within timeout, polling every interval.
+// The condition is checked once immediately before the first wait.
+func Eventually(tb Reporter, condition func() bool, timeout, interval time.Duration, msgAndArgs ...any) {
+	tb.Helper()
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	for !condition() {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			fail(tb, "Eventually", "condition to become true",
+				"still false after "+time.Since(start).Round(time.Millisecond).String(), msgAndArgs)
+
+			return
+		}
+
+		time.Sleep(min(interval, remaining))
+	}
+}
+
+// Never asserts that condition stays false for duration, polling every interval.
+// The condition is checked once immediately before the first wait.
+func Never(tb Reporter, condition func() bool, duration, interval time.Duration, msgAndArgs ...any) {
+	tb.Helper()
+
+	start := time.Now()
+	deadline := start.Add(duration)
+
+	for {
+		if condition() {
+			fail(tb, "Never", "condition to stay false for "+duration.String(),
+				"became true after "+time.Since(start).Round(time.Millisecond).String(), msgAndArgs)
+
+			return
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return
+		}
+
+		time.Sleep(min(interval, remaining))
 	}
 }
 
 // failStr reports a string assertion failure.
-func failStr(tb testing.TB, name, label, s, search, status string) {
+func failStr(tb Reporter, name, label, s, search, status string, msgAndArgs []any) {
 	tb.Helper()
-	tb.Errorf(
+	failf(tb, msgAndArgs,
 		"testastic: assertion failed\n\n  %s\n    string: %s\n    %s: %s (%s)",
 		name, green(formatVal(s)), label, red(formatVal(search)), status,
 	)
 }
 
 // Contains asserts that s contains substring.
-func Contains(tb testing.TB, s, substring string) {
+func Contains(tb Reporter, s, substring string, msgAndArgs ...any) {
 	tb.Helper()
 
 	if !strings.Contains(s, substring) {
-		failStr(tb, "Contains", "substring", s, substring, "not found")
+		failStr(tb, "Contains", "substring", s, substring, "not found", msgAndArgs)
 	}
 }
 
 // NotContains asserts that s does not contain substring.
-func NotContains(tb testing.TB, s, substring string) {
+func NotContains(tb Reporter, s, substring string, msgAndArgs ...any) {
 	tb.Helper()
 
 	if strings.Contains(s, substring) {
-		failStr(tb, "NotContains", "substring", s, substring, "found")
+		failStr(tb, "NotContains", "substring", s, substring, "found", msgAndArgs)
 	}
 }
 
 // HasPrefix asserts that s has the given prefix.
-func HasPrefix(tb testing.TB, s, prefix string) {
+func HasPrefix(tb Reporter, s, prefix string, msgAndArgs ...any) {
 	tb.Helper()
 
 	if !strings.HasPrefix(s, prefix) {
-		failStr(tb, "HasPrefix", "prefix", s, prefix, "not found")
+		failStr(tb, "HasPrefix", "prefix", s, prefix, "not found", msgAndArgs)
 	}
 }
 
 // HasSuffix asserts that s has the given suffix.
-func HasSuffix(tb testing.TB, s, suffix string) {
+func HasSuffix(tb Reporter, s, suffix string, msgAndArgs ...any) {
 	tb.Helper()
 
 	if !strings.HasSuffix(s, suffix) {
-		failStr(tb, "HasSuffix", "suffix", s, suffix, "not found")
+		failStr(tb, "HasSuffix", "suffix", s, suffix, "not found", msgAndArgs)
 	}
 }
 
 // Matches asserts that s matches the given regular expression pattern.
-func Matches(tb testing.TB, s, pattern string) {
+func Matches(tb Reporter, s, pattern string, msgAndArgs ...any) {
 	tb.Helper()
 
 	re, err := regexp.Compile(pattern)
 	if err != nil {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  Matches\n    error: invalid pattern %q: %v",
 			pattern, err,
 		)
@@ -249,25 +629,25 @@ func Matches(tb testing.TB, s, pattern string) {
 	}
 
 	if !re.MatchString(s) {
-		failStr(tb, "Matches", "pattern", s, pattern, "no match")
+		failStr(tb, "Matches", "pattern", s, pattern, "no match", msgAndArgs)
 	}
 }
 
 // StringEmpty asserts that s is an empty string.
-func StringEmpty(tb testing.TB, s string) {
+func StringEmpty(tb Reporter, s string, msgAndArgs ...any) {
 	tb.Helper()
 
 	if s != "" {
-		fail(tb, "StringEmpty", `""`, formatVal(s))
+		fail(tb, "StringEmpty", `""`, formatVal(s), msgAndArgs)
 	}
 }
 
 // StringNotEmpty asserts that s is not an empty string.
-func StringNotEmpty(tb testing.TB, s string) {
+func StringNotEmpty(tb Reporter, s string, msgAndArgs ...any) {
 	tb.Helper()
 
 	if s == "" {
-		fail(tb, "StringNotEmpty", "non-empty string", `""`)
+		fail(tb, "StringNotEmpty", "non-empty string", `""`, msgAndArgs)
 	}
 }
 
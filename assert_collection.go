@@ -5,7 +5,7 @@ import (
 	"reflect"
 	"slices"
 	"strconv"
-	"testing"
+	"sync"
 )
 
 // Display truncation thresholds.
@@ -14,14 +14,20 @@ const (
 	maxMapDisplayLen   = 3
 )
 
+// Lener is implemented by collection types that report their own length,
+// so they work with Len, Empty, and NotEmpty.
+type Lener interface {
+	Len() int
+}
+
 // Len asserts that the collection has the expected length.
-// Works with slices, maps, strings, arrays, and channels.
-func Len(tb testing.TB, collection any, expected int) {
+// Works with slices, maps, strings, arrays, channels, *sync.Map, and types implementing Lener.
+func Len(tb Reporter, collection any, expected int, msgAndArgs ...any) {
 	tb.Helper()
 
 	actual := getLen(collection)
 	if actual == -1 {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  Len\n    error: cannot get length of %T",
 			collection,
 		)
@@ -30,21 +36,81 @@ func Len(tb testing.TB, collection any, expected int) {
 	}
 
 	if actual != expected {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  Len\n    expected: %s\n    actual:   %s",
 			red(strconv.Itoa(expected)), green(strconv.Itoa(actual)),
 		)
 	}
 }
 
+// LenGreater asserts that the collection has more than n elements.
+// Works with the same types as Len.
+func LenGreater(tb Reporter, collection any, n int, msgAndArgs ...any) {
+	tb.Helper()
+
+	actual, ok := lenOf(tb, "LenGreater", collection, msgAndArgs)
+	if ok && actual <= n {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  LenGreater\n    expected: %s\n    actual:   %s",
+			red(fmt.Sprintf("length > %d", n)), green(fmt.Sprintf("length %d", actual)),
+		)
+	}
+}
+
+// LenLess asserts that the collection has fewer than n elements.
+// Works with the same types as Len.
+func LenLess(tb Reporter, collection any, n int, msgAndArgs ...any) {
+	tb.Helper()
+
+	actual, ok := lenOf(tb, "LenLess", collection, msgAndArgs)
+	if ok && actual >= n {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  LenLess\n    expected: %s\n    actual:   %s",
+			red(fmt.Sprintf("length < %d", n)), green(fmt.Sprintf("length %d", actual)),
+		)
+	}
+}
+
+// LenBetween asserts that the collection has between minLen and maxLen elements, inclusive.
+// Works with the same types as Len.
+func LenBetween(tb Reporter, collection any, minLen, maxLen int, msgAndArgs ...any) {
+	tb.Helper()
+
+	actual, ok := lenOf(tb, "LenBetween", collection, msgAndArgs)
+	if ok && (actual < minLen || actual > maxLen) {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  LenBetween\n    expected: %s\n    actual:   %s",
+			red(fmt.Sprintf("length in [%d, %d]", minLen, maxLen)), green(fmt.Sprintf("length %d", actual)),
+		)
+	}
+}
+
+// lenOf returns the length of collection, reporting a failure for name if the
+// type has no length.
+func lenOf(tb Reporter, name string, collection any, msgAndArgs []any) (int, bool) {
+	tb.Helper()
+
+	length := getLen(collection)
+	if length == -1 {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  %s\n    error: cannot get length of %T",
+			name, collection,
+		)
+
+		return 0, false
+	}
+
+	return length, true
+}
+
 // Empty asserts that the collection is empty.
-// Works with slices, maps, strings, arrays, and channels.
-func Empty(tb testing.TB, collection any) {
+// Works with slices, maps, strings, arrays, channels, *sync.Map, and types implementing Lener.
+func Empty(tb Reporter, collection any, msgAndArgs ...any) {
 	tb.Helper()
 
 	length := getLen(collection)
 	if length == -1 {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  Empty\n    error: cannot get length of %T",
 			collection,
 		)
@@ -53,7 +119,7 @@ func Empty(tb testing.TB, collection any) {
 	}
 
 	if length != 0 {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  Empty\n    expected: %s\n    actual:   %s",
 			red("empty (length 0)"), green(fmt.Sprintf("length %d", length)),
 		)
@@ -61,13 +127,13 @@ func Empty(tb testing.TB, collection any) {
 }
 
 // NotEmpty asserts that the collection is not empty.
-// Works with slices, maps, strings, arrays, and channels.
-func NotEmpty(tb testing.TB, collection any) {
+// Works with slices, maps, strings, arrays, channels, *sync.Map, and types implementing Lener.
+func NotEmpty(tb Reporter, collection any, msgAndArgs ...any) {
 	tb.Helper()
 
 	length := getLen(collection)
 	if length == -1 {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  NotEmpty\n    error: cannot get length of %T",
 			collection,
 		)
@@ -76,7 +142,7 @@ func NotEmpty(tb testing.TB, collection any) {
 	}
 
 	if length == 0 {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  NotEmpty\n    expected: %s\n    actual:   %s",
 			red("non-empty"), green("empty (length 0)"),
 		)
@@ -84,37 +150,73 @@ func NotEmpty(tb testing.TB, collection any) {
 }
 
 // SliceContains asserts that slice contains element.
-func SliceContains[T comparable](tb testing.TB, slice []T, element T) {
+func SliceContains[T comparable](tb Reporter, slice []T, element T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if slices.Contains(slice, element) {
 		return
 	}
 
-	tb.Errorf(
+	failf(tb, msgAndArgs,
 		"testastic: assertion failed\n\n  SliceContains\n    slice:   %s\n    element: %s (not found)",
 		green(formatSlice(slice)), red(formatVal(element)),
 	)
 }
 
 // SliceNotContains asserts that slice does not contain element.
-func SliceNotContains[T comparable](tb testing.TB, slice []T, element T) {
+func SliceNotContains[T comparable](tb Reporter, slice []T, element T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if slices.Contains(slice, element) {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  SliceNotContains\n    slice:   %s\n    element: %s (found)",
 			green(formatSlice(slice)), red(formatVal(element)),
 		)
 	}
 }
 
+// SliceCount asserts that element occurs exactly count times in slice.
+func SliceCount[T comparable](tb Reporter, slice []T, element T, count int, msgAndArgs ...any) {
+	tb.Helper()
+
+	actual := 0
+
+	for _, v := range slice {
+		if v == element {
+			actual++
+		}
+	}
+
+	if actual != count {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  SliceCount\n    slice:    %s\n    element:  %s\n    expected: %s\n    actual:   %s",
+			formatSlice(slice), formatVal(element),
+			red(fmt.Sprintf("%d occurrences", count)), green(fmt.Sprintf("%d occurrences", actual)),
+		)
+	}
+}
+
+// SliceContainsFunc asserts that at least one element of slice satisfies pred.
+// Unlike SliceContains, T does not need to be comparable.
+func SliceContainsFunc[T any](tb Reporter, slice []T, pred func(T) bool, msgAndArgs ...any) {
+	tb.Helper()
+
+	if slices.ContainsFunc(slice, pred) {
+		return
+	}
+
+	failf(tb, msgAndArgs,
+		"testastic: assertion failed\n\n  SliceContainsFunc\n    slice:   %s (len %d)\n    element: %s",
+		green(formatSlice(slice)), len(slice), red("no element satisfies the predicate"),
+	)
+}
+
 // SliceEqual asserts that two slices are equal (same length and elements in same order).
-func SliceEqual[T comparable](tb testing.TB, expected, actual []T) {
+func SliceEqual[T comparable](tb Reporter, expected, actual []T, msgAndArgs ...any) {
 	tb.Helper()
 
 	if len(expected) != len(actual) {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  SliceEqual\n    expected: %s (len %d)\n    actual:   %s (len %d)",
 			red(formatSlice(expected)), len(expected), green(formatSlice(actual)), len(actual),
 		)
@@ -124,7 +226,7 @@ func SliceEqual[T comparable](tb testing.TB, expected, actual []T) {
 
 	for i := range expected {
 		if expected[i] != actual[i] {
-			tb.Errorf(
+			failf(tb, msgAndArgs,
 				"testastic: assertion failed\n\n  SliceEqual\n    diff at [%d]: %s != %s",
 				i, red(formatVal(expected[i])), green(formatVal(actual[i])),
 			)
@@ -135,11 +237,11 @@ func SliceEqual[T comparable](tb testing.TB, expected, actual []T) {
 }
 
 // MapHasKey asserts that the map contains the given key.
-func MapHasKey[K comparable, V any](tb testing.TB, m map[K]V, key K) {
+func MapHasKey[K comparable, V any](tb Reporter, m map[K]V, key K, msgAndArgs ...any) {
 	tb.Helper()
 
 	if _, ok := m[key]; !ok {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  MapHasKey\n    map: %s\n    key: %s (not found)",
 			green(formatMap(m)), red(formatVal(key)),
 		)
@@ -147,23 +249,45 @@ func MapHasKey[K comparable, V any](tb testing.TB, m map[K]V, key K) {
 }
 
 // MapNotHasKey asserts that the map does not contain the given key.
-func MapNotHasKey[K comparable, V any](tb testing.TB, m map[K]V, key K) {
+func MapNotHasKey[K comparable, V any](tb Reporter, m map[K]V, key K, msgAndArgs ...any) {
 	tb.Helper()
 
 	if _, ok := m[key]; ok {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  MapNotHasKey\n    map: %s\n    key: %s (found)",
 			green(formatMap(m)), red(formatVal(key)),
 		)
 	}
 }
 
+// MapHasKeyValue asserts that the map contains key with the given value.
+func MapHasKeyValue[K comparable, V comparable](tb Reporter, m map[K]V, key K, value V, msgAndArgs ...any) {
+	tb.Helper()
+
+	actual, ok := m[key]
+	if !ok {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  MapHasKeyValue\n    map: %s\n    key: %s (not found)",
+			green(formatMap(m)), red(formatVal(key)),
+		)
+
+		return
+	}
+
+	if actual != value {
+		failf(tb, msgAndArgs,
+			"testastic: assertion failed\n\n  MapHasKeyValue\n    key:      %s\n    expected: %s\n    actual:   %s",
+			formatVal(key), red(formatVal(value)), green(formatVal(actual)),
+		)
+	}
+}
+
 // MapEqual asserts that two maps are equal.
-func MapEqual[K comparable, V comparable](tb testing.TB, expected, actual map[K]V) {
+func MapEqual[K comparable, V comparable](tb Reporter, expected, actual map[K]V, msgAndArgs ...any) {
 	tb.Helper()
 
 	if len(expected) != len(actual) {
-		tb.Errorf(
+		failf(tb, msgAndArgs,
 			"testastic: assertion failed\n\n  MapEqual\n    expected: %s (len %d)\n    actual:   %s (len %d)",
 			red(formatMap(expected)), len(expected), green(formatMap(actual)), len(actual),
 		)
@@ -174,7 +298,7 @@ func MapEqual[K comparable, V comparable](tb testing.TB, expected, actual map[K]
 	for k, ev := range expected {
 		av, ok := actual[k]
 		if !ok {
-			tb.Errorf(
+			failf(tb, msgAndArgs,
 				"testastic: assertion failed\n\n  MapEqual\n    missing key: %s",
 				red(formatVal(k)),
 			)
@@ -183,7 +307,7 @@ func MapEqual[K comparable, V comparable](tb testing.TB, expected, actual map[K]
 		}
 
 		if ev != av {
-			tb.Errorf(
+			failf(tb, msgAndArgs,
 				"testastic: assertion failed\n\n  MapEqual\n    diff at key %s: %s != %s",
 				formatVal(k), red(formatVal(ev)), green(formatVal(av)),
 			)
@@ -200,6 +324,27 @@ func getLen(collection any) int {
 	}
 
 	v := reflect.ValueOf(collection)
+
+	// A typed nil pointer has no length; calling Len or Range on it would panic.
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return -1
+	}
+
+	switch c := collection.(type) {
+	case Lener:
+		return c.Len()
+	case *sync.Map:
+		n := 0
+
+		c.Range(func(_, _ any) bool {
+			n++
+
+			return true
+		})
+
+		return n
+	}
+
 	//nolint:exhaustive // Only collection types have length.
 	switch v.Kind() {
 	case reflect.Slice, reflect.Map, reflect.String, reflect.Array, reflect.Chan:
@@ -1,10 +1,14 @@
 package testastic_test
 
 import (
+	"container/list"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/monkescience/testastic"
 )
@@ -93,6 +97,138 @@ func TestDeepEqual_Fail(t *testing.T) {
 	}
 }
 
+func TestDeepEqual_Diff(t *testing.T) {
+	type user struct {
+		Name  string
+		Age   int
+		Roles []string
+	}
+
+	type secret struct {
+		value string
+	}
+
+	// GIVEN: structs that differ in one field, and structs that differ only in unexported fields
+	expected := user{Name: "Alice", Age: 30, Roles: []string{"admin"}}
+	actual := user{Name: "Alice", Age: 31, Roles: []string{"admin"}}
+	r := &recordingReporter{}
+
+	// WHEN: asserting deep equality
+	testastic.DeepEqual(r, expected, actual)
+	testastic.DeepEqual(r, secret{value: "a"}, secret{value: "b"})
+
+	// THEN: the failures show a line-level diff of the JSON or %+v renderings
+	testastic.Len(t, r.errors, 2)
+	testastic.Contains(t, r.errors[0], `-   "Age": 30,`)
+	testastic.Contains(t, r.errors[0], `+   "Age": 31,`)
+	testastic.Contains(t, r.errors[0], `    "Name": "Alice",`)
+	testastic.Contains(t, r.errors[1], "- {value:a}")
+	testastic.Contains(t, r.errors[1], "+ {value:b}")
+}
+
+func TestEqualDeref(t *testing.T) {
+	// GIVEN: two pointers to equal values
+	a, b := 5, 5
+
+	// WHEN: asserting the pointees are equal
+	// THEN: the test passes
+	testastic.EqualDeref(t, &a, &b)
+}
+
+func TestEqualDeref_Fail(t *testing.T) {
+	// GIVEN: pointers to different values and a nil pointer
+	a, b := 5, 6
+	r := &recordingReporter{}
+
+	// WHEN: asserting the pointees are equal
+	testastic.EqualDeref(r, &a, &b)
+	testastic.EqualDeref(r, &a, nil)
+
+	// THEN: both fail, the second reporting nil
+	if len(r.errors) != 2 {
+		t.Fatalf("expected 2 failures, got %v", r.errors)
+	}
+
+	testastic.Contains(t, r.errors[1], "nil")
+}
+
+func TestEqualValue(t *testing.T) {
+	// GIVEN: values behind different levels of indirection
+	var nilPtr *int
+
+	n := 5
+	p := &n
+
+	// WHEN: comparing after dereferencing
+	// THEN: equal pointees match and nil pointers equal nil
+	testastic.EqualValue(t, 5, &n)
+	testastic.EqualValue(t, &p, 5)
+	testastic.EqualValue(t, nil, nilPtr)
+
+	// WHEN: the pointee differs
+	mt := newMockT()
+	testastic.EqualValue(mt, 6, &n)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected EqualValue to fail")
+	}
+}
+
+func TestSame(t *testing.T) {
+	// GIVEN: a pointer and a second pointer to an equal value
+	a := &stack{items: []int{1}}
+	b := &stack{items: []int{1}}
+
+	// WHEN: asserting identity
+	// THEN: the same pointer is Same, the equal one is NotSame
+	testastic.Same(t, a, a)
+	testastic.NotSame(t, a, b)
+}
+
+func TestSame_Fail(t *testing.T) {
+	// GIVEN: two pointers to equal values and a non-pointer value
+	a := &stack{items: []int{1}}
+	b := &stack{items: []int{1}}
+	r := &recordingReporter{}
+
+	// WHEN: asserting identity
+	testastic.Same(r, a, b)
+	testastic.NotSame(r, a, a)
+	testastic.Same(r, 1, 1)
+
+	// THEN: each assertion fails, the last for the non-pointer kind
+	if len(r.errors) != 3 {
+		t.Fatalf("expected 3 failures, got %v", r.errors)
+	}
+
+	testastic.Contains(t, r.errors[2], "expected pointer-like values, got int and int")
+}
+
+func TestIsType(t *testing.T) {
+	// GIVEN: values decoded into any
+	var decoded any = map[string]any{"a": 1.0}
+
+	var nilStack *stack
+
+	// WHEN: asserting their dynamic types
+	// THEN: matching types pass
+	testastic.IsType(t, map[string]any{}, decoded)
+	testastic.IsType(t, (*stack)(nil), nilStack)
+	testastic.IsType(t, nil, nil)
+
+	// WHEN: the types differ, including an untyped nil
+	r := &recordingReporter{}
+	testastic.IsType(r, []any{}, decoded)
+	testastic.IsType(r, nil, nilStack)
+
+	// THEN: both type names are reported
+	testastic.Len(t, r.errors, 2)
+	testastic.Contains(t, r.errors[0], "[]interface {}")
+	testastic.Contains(t, r.errors[0], "map[string]interface {}")
+	testastic.Contains(t, r.errors[1], "nil (no type)")
+}
+
 // --- Nil Tests ---
 
 func TestNil_Pass(t *testing.T) {
@@ -253,6 +389,41 @@ func TestErrorIs_Fail(t *testing.T) {
 	}
 }
 
+// validationError is a typed error for testing ErrorAs.
+type validationError struct {
+	Field string
+}
+
+func (e *validationError) Error() string { return "invalid " + e.Field }
+
+func TestErrorAs_Pass(t *testing.T) {
+	// GIVEN: a wrapped typed error
+	err := fmt.Errorf("create user: %w", &validationError{Field: "email"})
+
+	// WHEN: asserting the error type
+	got := testastic.ErrorAs[*validationError](t, err)
+
+	// THEN: the typed error is returned for chaining
+	testastic.Equal(t, "email", got.Field)
+}
+
+func TestErrorAs_Fail(t *testing.T) {
+	// GIVEN: an error of a different type
+	r := &recordingReporter{}
+
+	// WHEN: asserting the error type
+	got := testastic.ErrorAs[*validationError](r, errors.New("boom"))
+
+	// THEN: the test fails fatally and the zero value is returned
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "*testastic_test.validationError") {
+		t.Errorf("expected fatal naming the error type, got %v", r.fatals)
+	}
+
+	if got != nil {
+		t.Errorf("expected nil typed error, got %v", got)
+	}
+}
+
 func TestErrorContains_Pass(t *testing.T) {
 	// GIVEN: an error containing a substring
 	// WHEN: asserting error contains the substring
@@ -381,6 +552,189 @@ func TestBetween_Fail(t *testing.T) {
 	}
 }
 
+func TestNotBetween_Pass(t *testing.T) {
+	// GIVEN: a value outside the range
+	// WHEN: asserting not between
+	// THEN: the test passes
+	testastic.NotBetween(t, 0, 1, 10)
+	testastic.NotBetween(t, 11, 1, 10)
+}
+
+func TestNotBetween_Fail(t *testing.T) {
+	// GIVEN: values within the range (inclusive)
+	for _, value := range []int{1, 5, 10} {
+		mt := newMockT()
+
+		// WHEN: asserting not between
+		testastic.NotBetween(mt, value, 1, 10)
+
+		// THEN: the test fails
+		if !mt.failed {
+			t.Errorf("expected NotBetween to fail for %d", value)
+		}
+	}
+}
+
+func TestEqualWithin_Pass(t *testing.T) {
+	// GIVEN: floats that differ by less than delta
+	// WHEN: asserting equal within delta
+	// THEN: the test passes
+	testastic.EqualWithin(t, 0.3, 0.1+0.2, 1e-9)
+	testastic.EqualWithin(t, 1.0, 1.5, 0.5)
+}
+
+func TestEqualWithin_Fail(t *testing.T) {
+	// GIVEN: floats that differ by more than delta
+	r := &recordingReporter{}
+
+	// WHEN: asserting equal within delta
+	testastic.EqualWithin(r, 1.0, 1.5, 0.1)
+
+	// THEN: the test fails and reports the difference
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "difference: 0.5") {
+		t.Errorf("expected EqualWithin to fail with difference, got %v", r.errors)
+	}
+}
+
+func TestInEpsilon_Pass(t *testing.T) {
+	// GIVEN: values within the relative tolerance
+	// WHEN: asserting in epsilon
+	// THEN: the test passes
+	testastic.InEpsilon(t, 1e12, 1e12+1e9, 0.01)
+	testastic.InEpsilon(t, 1e-12, 1.001e-12, 0.01)
+	testastic.InEpsilon(t, 0, 0, 0.01)
+}
+
+func TestInEpsilon_Fail(t *testing.T) {
+	// GIVEN: values outside the relative tolerance, and a zero expected value
+	for _, tc := range [][2]float64{{100, 120}, {0, 1e-12}} {
+		mt := newMockT()
+
+		// WHEN: asserting in epsilon
+		testastic.InEpsilon(mt, tc[0], tc[1], 0.1)
+
+		// THEN: the test fails
+		if !mt.failed {
+			t.Errorf("expected InEpsilon to fail for %v", tc)
+		}
+	}
+}
+
+func TestBeforeAfter(t *testing.T) {
+	// GIVEN: two times a second apart
+	earlier := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := earlier.Add(time.Second)
+
+	// WHEN: asserting their order
+	// THEN: the correct order passes
+	testastic.Before(t, earlier, later)
+	testastic.After(t, later, earlier)
+
+	// WHEN: asserting the wrong order, or equal times
+	r := &recordingReporter{}
+	testastic.Before(r, later, earlier)
+	testastic.After(r, earlier, earlier)
+
+	// THEN: both fail with RFC 3339 times
+	testastic.Len(t, r.errors, 2)
+	testastic.Contains(t, r.errors[0], "2024-01-02T03:04:06Z")
+	testastic.Contains(t, r.errors[0], "not before")
+}
+
+func TestTimeEqual(t *testing.T) {
+	// GIVEN: the same instant in two locations and a time 2s later
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sameInstant := base.In(time.FixedZone("CET", 3600))
+	later := base.Add(2 * time.Second)
+
+	// WHEN: comparing within a tolerance
+	// THEN: times within the tolerance pass
+	testastic.TimeEqual(t, base, sameInstant, 0)
+	testastic.TimeEqual(t, base, later, 2*time.Second)
+
+	// WHEN: the difference exceeds the tolerance
+	r := &recordingReporter{}
+	testastic.TimeEqual(r, base, later, time.Second)
+
+	// THEN: the difference is reported
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], "difference: 2s")
+}
+
+// --- Polling Tests ---
+
+func TestEventually_Pass(t *testing.T) {
+	// GIVEN: a condition that becomes true after a few polls
+	calls := 0
+	condition := func() bool {
+		calls++
+
+		return calls >= 3
+	}
+
+	// WHEN: asserting eventually
+	// THEN: the test passes
+	testastic.Eventually(t, condition, time.Second, time.Millisecond)
+}
+
+func TestEventually_ChecksImmediately(t *testing.T) {
+	// GIVEN: a condition that is already true
+	calls := 0
+	condition := func() bool {
+		calls++
+
+		return true
+	}
+
+	// WHEN: asserting eventually with a long interval
+	start := time.Now()
+	testastic.Eventually(t, condition, time.Second, time.Hour)
+
+	// THEN: the condition is checked once without waiting
+	if calls != 1 || time.Since(start) > 100*time.Millisecond {
+		t.Errorf("expected a single immediate check, got %d calls", calls)
+	}
+}
+
+func TestEventually_Fail(t *testing.T) {
+	// GIVEN: a condition that never becomes true
+	mt := newMockT()
+
+	// WHEN: asserting eventually
+	testastic.Eventually(mt, func() bool { return false }, 20*time.Millisecond, 5*time.Millisecond)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected Eventually to fail")
+	}
+}
+
+func TestNever_Pass(t *testing.T) {
+	// GIVEN: a condition that stays false
+	// WHEN: asserting never
+	// THEN: the test passes
+	testastic.Never(t, func() bool { return false }, 20*time.Millisecond, 5*time.Millisecond)
+}
+
+func TestNever_Fail(t *testing.T) {
+	// GIVEN: a condition that becomes true during the window
+	mt := newMockT()
+	calls := 0
+	condition := func() bool {
+		calls++
+
+		return calls >= 2
+	}
+
+	// WHEN: asserting never
+	testastic.Never(mt, condition, time.Second, time.Millisecond)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected Never to fail")
+	}
+}
+
 // --- String Tests ---
 
 func TestContains_Pass(t *testing.T) {
@@ -547,6 +901,80 @@ func TestLen_Fail(t *testing.T) {
 	}
 }
 
+// stack is a user collection type implementing testastic.Lener.
+type stack struct {
+	items []int
+}
+
+func (s *stack) Len() int { return len(s.items) }
+
+func TestLen_Lener(t *testing.T) {
+	// GIVEN: a user type exposing Len()
+	s := &stack{items: []int{1, 2}}
+
+	// WHEN: asserting length and emptiness
+	// THEN: the Len method is used
+	testastic.Len(t, s, 2)
+	testastic.NotEmpty(t, s)
+	testastic.Empty(t, &stack{})
+}
+
+func TestLen_SyncMap(t *testing.T) {
+	// GIVEN: a sync.Map with entries
+	var m sync.Map
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+
+	// WHEN: asserting length and emptiness
+	// THEN: entries are counted via Range
+	testastic.Len(t, &m, 2)
+	testastic.NotEmpty(t, &m)
+	testastic.Empty(t, &sync.Map{})
+}
+
+func TestLen_NilPointers(t *testing.T) {
+	// GIVEN: typed nil pointers to collection types with a Len or Range method
+	r := &recordingReporter{}
+
+	// WHEN: asserting their length
+	testastic.Len(r, (*list.List)(nil), 0)
+	testastic.Len(r, (*sync.Map)(nil), 0)
+
+	// THEN: both are reported as having no length instead of panicking
+	testastic.Len(t, r.errors, 2)
+	testastic.Contains(t, r.errors[0], "cannot get length of *list.List")
+	testastic.Contains(t, r.errors[1], "cannot get length of *sync.Map")
+}
+
+func TestLenComparisons_Pass(t *testing.T) {
+	// GIVEN: collections with known lengths
+	// WHEN: asserting length bounds
+	// THEN: the test passes
+	testastic.LenGreater(t, []int{1, 2, 3}, 2)
+	testastic.LenLess(t, "hi", 3)
+	testastic.LenBetween(t, map[string]int{"a": 1, "b": 2}, 2, 4)
+}
+
+func TestLenComparisons_Fail(t *testing.T) {
+	// GIVEN: a collection of length 3
+	r := &recordingReporter{}
+
+	// WHEN: asserting bounds it does not satisfy, and a value without length
+	testastic.LenGreater(r, []int{1, 2, 3}, 3)
+	testastic.LenLess(r, []int{1, 2, 3}, 3)
+	testastic.LenBetween(r, []int{1, 2, 3}, 4, 5)
+	testastic.LenGreater(r, 42, 0)
+
+	// THEN: each assertion fails, the last with the unsupported type message
+	if len(r.errors) != 4 {
+		t.Fatalf("expected 4 failures, got %v", r.errors)
+	}
+
+	testastic.Contains(t, r.errors[2], "length in [4, 5]")
+	testastic.Contains(t, r.errors[3], "cannot get length of int")
+}
+
 func TestEmpty_Pass(t *testing.T) {
 	// GIVEN: empty collections
 	// WHEN: asserting empty
@@ -632,6 +1060,54 @@ func TestSliceNotContains_Fail(t *testing.T) {
 	}
 }
 
+func TestSliceCount_Pass(t *testing.T) {
+	// GIVEN: a slice with a repeated element
+	// WHEN: asserting the number of occurrences
+	// THEN: the test passes
+	testastic.SliceCount(t, []string{"a", "b", "a"}, "a", 2)
+	testastic.SliceCount(t, []string{"a", "b", "a"}, "c", 0)
+}
+
+func TestSliceCount_Fail(t *testing.T) {
+	// GIVEN: a slice with a repeated element
+	r := &recordingReporter{}
+
+	// WHEN: asserting a different number of occurrences
+	testastic.SliceCount(r, []int{1, 2, 1, 1}, 1, 2)
+
+	// THEN: both counts are reported
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], "2 occurrences")
+	testastic.Contains(t, r.errors[0], "3 occurrences")
+}
+
+func TestSliceContainsFunc_Pass(t *testing.T) {
+	// GIVEN: a slice of non-comparable elements with a matching element
+	type user struct {
+		Name string
+		Tags []string
+	}
+
+	users := []user{{Name: "Bob"}, {Name: "Alice", Tags: []string{"admin"}}}
+
+	// WHEN: asserting slice contains func
+	// THEN: the test passes
+	testastic.SliceContainsFunc(t, users, func(u user) bool { return u.Name == "Alice" })
+}
+
+func TestSliceContainsFunc_Fail(t *testing.T) {
+	// GIVEN: a slice without a matching element
+	r := &recordingReporter{}
+
+	// WHEN: asserting slice contains func
+	testastic.SliceContainsFunc(r, [][]int{{1}, {2, 3}}, func(s []int) bool { return len(s) > 2 })
+
+	// THEN: the test fails and reports the slice length
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "(len 2)") {
+		t.Errorf("expected SliceContainsFunc failure with length, got %v", r.errors)
+	}
+}
+
 func TestSliceEqual_Pass(t *testing.T) {
 	// GIVEN: two equal slices
 	// WHEN: asserting slice equal
@@ -686,6 +1162,31 @@ func TestMapHasKey_Fail(t *testing.T) {
 	}
 }
 
+func TestMapHasKeyValue_Pass(t *testing.T) {
+	// GIVEN: a map containing a key with a specific value
+	// WHEN: asserting the key value pair
+	// THEN: the test passes
+	testastic.MapHasKeyValue(t, map[string]int{"a": 1, "b": 2}, "b", 2)
+}
+
+func TestMapHasKeyValue_Fail(t *testing.T) {
+	// GIVEN: a map
+	m := map[string]int{"a": 1}
+	r := &recordingReporter{}
+
+	// WHEN: asserting a missing key and a key with a different value
+	testastic.MapHasKeyValue(r, m, "b", 1)
+	testastic.MapHasKeyValue(r, m, "a", 2)
+
+	// THEN: both fail with distinct messages
+	if len(r.errors) != 2 {
+		t.Fatalf("expected 2 failures, got %v", r.errors)
+	}
+
+	testastic.Contains(t, r.errors[0], "(not found)")
+	testastic.Contains(t, r.errors[1], "expected: 2")
+}
+
 func TestMapNotHasKey_Pass(t *testing.T) {
 	// GIVEN: a map not containing a specific key
 	// WHEN: asserting map not has key
@@ -761,3 +1262,112 @@ func TestErrorMessageFormat(t *testing.T) {
 		t.Error("expected error message to contain assertion name")
 	}
 }
+
+// --- Reporter Tests ---
+
+// recordingReporter implements testastic.Reporter without embedding testing.TB.
+type recordingReporter struct {
+	errors []string
+	fatals []string
+	logs   []string
+}
+
+func (r *recordingReporter) Helper() {}
+
+func (r *recordingReporter) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingReporter) Fatalf(format string, args ...any) {
+	r.fatals = append(r.fatals, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingReporter) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func TestReporter_CustomImplementation(t *testing.T) {
+	// GIVEN: a custom reporter outside of the testing package
+	r := &recordingReporter{}
+
+	// WHEN: running passing and failing assertions against it
+	testastic.Equal(r, 1, 1)
+	testastic.Equal(r, 1, 2)
+	testastic.Contains(r, "hello", "ell")
+
+	// THEN: only the failing assertion is reported
+	if len(r.errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(r.errors), r.errors)
+	}
+
+	if !strings.Contains(r.errors[0], "Equal") {
+		t.Errorf("expected error to mention Equal, got: %s", r.errors[0])
+	}
+}
+
+func TestReporter_TestingTB(t *testing.T) {
+	// GIVEN: a testing.TB
+	// WHEN: using it as a Reporter
+	var r testastic.Reporter = t
+
+	// THEN: assertions accept it
+	testastic.True(r, true)
+}
+
+func TestSetColorEnabled(t *testing.T) {
+	// GIVEN: colors forced on
+	testastic.SetColorEnabled(true)
+	t.Cleanup(testastic.ResetColorDetection)
+
+	r := &recordingReporter{}
+
+	// WHEN: an assertion fails
+	testastic.Equal(r, "Alice", "Bob")
+
+	// THEN: the failure contains ANSI color codes
+	testastic.Contains(t, r.errors[0], "\033[31m")
+
+	// WHEN: colors are forced off
+	testastic.SetColorEnabled(false)
+
+	r = &recordingReporter{}
+	testastic.Equal(r, "Alice", "Bob")
+
+	// THEN: the failure is plain text
+	testastic.NotContains(t, r.errors[0], "\033[")
+}
+
+func TestRequire(t *testing.T) {
+	// GIVEN: a reporter wrapped with Require
+	r := &recordingReporter{}
+	require := testastic.Require(r)
+
+	expectedFile := filepath.Join(t.TempDir(), "user.expected.json")
+	writeTestFile(t, expectedFile, `{"name": "Alice"}`)
+
+	// WHEN: assertions fail through it
+	testastic.Equal(require, "Alice", "Bob")
+	testastic.RequireNoError(r, errors.New("boom"))
+	testastic.RequireJSON(r, expectedFile, `{"name": "Bob"}`)
+
+	// THEN: the failures are reported as fatal
+	testastic.Len(t, r.errors, 0)
+	testastic.Len(t, r.fatals, 3)
+	testastic.Contains(t, r.fatals[0], "Equal")
+}
+
+func TestAssertionMessage(t *testing.T) {
+	// GIVEN: failing assertions with custom messages
+	r := &recordingReporter{}
+
+	// WHEN: passing a format string with arguments, a plain message, and a non-string
+	testastic.Equal(r, "Alice", "Bob", "user %d mismatch", 7)
+	testastic.Contains(r, "hello", "x", "greeting")
+	testastic.Len(r, []int{1}, 2, 42)
+
+	// THEN: each message precedes the standard failure block
+	testastic.Len(t, r.errors, 3)
+	testastic.HasPrefix(t, r.errors[0], "user 7 mismatch\ntestastic: assertion failed")
+	testastic.HasPrefix(t, r.errors[1], "greeting\ntestastic: assertion failed")
+	testastic.HasPrefix(t, r.errors[2], "42\ntestastic: assertion failed")
+}
@@ -0,0 +1,40 @@
+package testastic
+
+import "slices"
+
+// JSONAsserter runs JSON assertions with a shared set of options, so a suite with a
+// common comparison policy does not repeat the option list on every call.
+type JSONAsserter struct {
+	opts []Option
+}
+
+// NewJSON returns a JSONAsserter that applies opts to every assertion.
+//
+// Example:
+//
+//	api := testastic.NewJSON(testastic.IgnoreFields("id", "created_at"), testastic.IgnoreArrayOrder())
+//	api.Assert(t, "testdata/user.expected.json", resp.Body)
+//	api.Assert(t, "testdata/users.expected.json", listResp.Body, testastic.MaxDiffs(5))
+func NewJSON(opts ...Option) *JSONAsserter {
+	return &JSONAsserter{opts: slices.Clone(opts)}
+}
+
+// Assert is like AssertJSON with the shared options. Additional opts are applied
+// after the shared ones. Actual can be any type accepted by AssertJSON.
+func (a *JSONAsserter) Assert(tb Reporter, expectedFile string, actual any, opts ...Option) {
+	tb.Helper()
+
+	AssertJSON(tb, expectedFile, actual, a.options(opts)...)
+}
+
+// AssertString is like AssertJSONString with the shared options.
+func (a *JSONAsserter) AssertString(tb Reporter, expected string, actual any, opts ...Option) {
+	tb.Helper()
+
+	AssertJSONString(tb, expected, actual, a.options(opts)...)
+}
+
+// options returns the shared options followed by opts.
+func (a *JSONAsserter) options(opts []Option) []Option {
+	return append(slices.Clone(a.opts), opts...)
+}
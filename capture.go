@@ -0,0 +1,199 @@
+package testastic
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// captureMatcher matches any value and records it under name for ref matchers.
+type captureMatcher struct {
+	name string
+}
+
+func (m *captureMatcher) Match(_ any) bool {
+	return true
+}
+
+func (m *captureMatcher) String() string {
+	return "{{capture " + strconv.Quote(m.name) + "}}"
+}
+
+// refMatcher matches the value recorded by the capture matcher of the same name.
+// The comparison resolves it after walking the whole document; on its own, without
+// a captured value to compare against, it matches any value.
+type refMatcher struct {
+	name string
+}
+
+func (m *refMatcher) Match(_ any) bool {
+	return true
+}
+
+func (m *refMatcher) String() string {
+	return "{{ref " + strconv.Quote(m.name) + "}}"
+}
+
+// pendingRef is a ref matcher and its actual value, resolved once all captures are known.
+type pendingRef struct {
+	name   string
+	actual any
+}
+
+// Capture returns a matcher that accepts any value and records it under name, so
+// other fields can be checked against it with Ref. Captured values are available
+// after the assertion through WithCaptures.
+//
+// Example expected file:
+//
+//	{"request_id": "{{capture \"rid\"}}", "response": {"request_id": "{{ref \"rid\"}}"}}
+func Capture(name string) Matcher {
+	return &captureMatcher{name: name}
+}
+
+// Ref returns a matcher that requires the value to equal the value captured under
+// name anywhere in the same document. A ref may appear before its capture. It fails
+// if nothing was captured under name.
+func Ref(name string) Matcher {
+	return &refMatcher{name: name}
+}
+
+// WithCaptures makes the assertion store the values recorded by capture matchers in
+// m, keyed by capture name, for use in follow-up assertions.
+//
+// Example:
+//
+//	captures := map[string]any{}
+//	testastic.AssertJSON(t, "testdata/create.expected.json", resp.Body, testastic.WithCaptures(captures))
+//	id := captures["id"]
+func WithCaptures(m map[string]any) Option {
+	return func(c *Config) {
+		c.Captures = m
+	}
+}
+
+// capture records a value captured under name.
+func (c *Config) capture(name string, actual any) {
+	if c.Captures == nil {
+		c.Captures = make(map[string]any)
+	}
+
+	c.Captures[name] = actual
+}
+
+// addRef records a ref matcher at path for resolution after the document is walked.
+// A later comparison of the same path replaces earlier ones.
+func (c *Config) addRef(path, name string, actual any) {
+	if c.refs == nil {
+		c.refs = make(map[string]pendingRef)
+	}
+
+	c.refs[path] = pendingRef{name: name, actual: actual}
+}
+
+// matchState is a snapshot of the captures and refs recorded so far, so that the side
+// effects of a trial comparison can be undone.
+type matchState struct {
+	captures map[string]any
+	refs     map[string]pendingRef
+}
+
+// saveMatchState returns a snapshot of the recorded captures and refs.
+func (c *Config) saveMatchState() matchState {
+	return matchState{captures: maps.Clone(c.Captures), refs: maps.Clone(c.refs)}
+}
+
+// restoreMatchState undoes captures and refs recorded since s was taken. The
+// Captures map is restored in place, since it may be the map given to WithCaptures.
+func (c *Config) restoreMatchState(s matchState) {
+	if c.Captures != nil {
+		clear(c.Captures)
+		maps.Copy(c.Captures, s.captures)
+	}
+
+	c.refs = s.refs
+}
+
+// unresolvedRefsSince counts the refs recorded since s was taken whose value does not
+// equal a value captured so far, including refs whose capture is still pending.
+func (c *Config) unresolvedRefsSince(s matchState) int {
+	n := 0
+
+	for path, ref := range c.refs {
+		if old, ok := s.refs[path]; ok && reflect.DeepEqual(old, ref) {
+			continue
+		}
+
+		captured, ok := c.Captures[ref.name]
+		if !ok || !reflect.DeepEqual(captured, ref.actual) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// tryCompare compares like compare without keeping the captures and refs it records,
+// e.g. to try candidates for an element of an unordered array. It returns a mismatch
+// score: the number of differences plus the refs not resolving to a captured value,
+// since a ref matches any value until it is resolved.
+func tryCompare(expected, actual any, path string, cfg *Config) int {
+	state := cfg.saveMatchState()
+	score := len(compare(expected, actual, path, cfg)) + cfg.unresolvedRefsSince(state)
+	cfg.restoreMatchState(state)
+
+	return score
+}
+
+// resolveRefs compares the recorded ref matchers against the captured values.
+func (c *Config) resolveRefs() []Difference {
+	paths := make([]string, 0, len(c.refs))
+	for path := range c.refs {
+		paths = append(paths, path)
+	}
+
+	slices.Sort(paths)
+
+	var diffs []Difference
+
+	for _, path := range paths {
+		ref := c.refs[path]
+
+		captured, ok := c.Captures[ref.name]
+		if !ok {
+			diffs = append(diffs, Difference{
+				Path:     path,
+				Expected: Ref(ref.name),
+				Actual:   ref.actual,
+				Type:     DiffMatcherFailed,
+				Reason:   fmt.Sprintf("no value captured as %q", ref.name),
+			})
+
+			continue
+		}
+
+		if !reflect.DeepEqual(captured, ref.actual) {
+			diffs = append(diffs, Difference{
+				Path:     path,
+				Expected: captured,
+				Actual:   ref.actual,
+				Type:     DiffMatcherFailed,
+				Reason:   fmt.Sprintf("value differs from the one captured as %q", ref.name),
+			})
+		}
+	}
+
+	return diffs
+}
+
+// compareDocument compares a whole document like compare and then resolves ref
+// matchers against the values captured anywhere in it.
+func compareDocument(expected, actual any, path string, cfg *Config) []Difference {
+	cfg.refs = nil
+
+	diffs := compare(expected, actual, path, cfg)
+
+	return append(diffs, cfg.resolveRefs()...)
+}
@@ -2,6 +2,7 @@ package testastic
 
 import (
 	"os"
+	"sync"
 
 	"golang.org/x/term"
 )
@@ -11,16 +12,45 @@ const (
 	colorRed   = "\033[31m"
 	colorGreen = "\033[32m"
 	colorReset = "\033[0m"
+
+	// Reverse video highlights the changed span within a colored line.
+	colorReverse    = "\033[7m"
+	colorReverseOff = "\033[27m"
+)
+
+// colorsEnabled caches the color detection result or the value set by SetColorEnabled.
+var (
+	colorsEnabled *bool
+	colorsMu      sync.Mutex
 )
 
-// colorsEnabled caches the color detection result.
-var colorsEnabled *bool
+// SetColorEnabled forces colored output on or off, overriding the detection from
+// environment and terminal. Use it for deterministic output in tests that inspect
+// FormatDiff or FormatHTMLDiff results, or to force colors when piping to a pager.
+func SetColorEnabled(enabled bool) {
+	colorsMu.Lock()
+	defer colorsMu.Unlock()
+
+	colorsEnabled = &enabled
+}
+
+// ResetColorDetection discards the value set by SetColorEnabled, so colors are
+// detected again from environment and terminal.
+func ResetColorDetection() {
+	colorsMu.Lock()
+	defer colorsMu.Unlock()
+
+	colorsEnabled = nil
+}
 
 // useColors returns true if colored output should be used.
 // Colors are enabled when stdout is a terminal (not piped),
 // NO_COLOR env var is not set, CI env var is not set,
 // and TERM is not "dumb".
 func useColors() bool {
+	colorsMu.Lock()
+	defer colorsMu.Unlock()
+
 	if colorsEnabled != nil {
 		return *colorsEnabled
 	}
@@ -74,3 +104,13 @@ func red(text string) string {
 func green(text string) string {
 	return colorize(text, colorGreen)
 }
+
+// colorizeSpan colors a line and highlights the span between prefix and suffix
+// in reverse video, so the changed part of a line stands out.
+func colorizeSpan(prefix, span, suffix, color string) string {
+	if !useColors() {
+		return prefix + span + suffix
+	}
+
+	return color + prefix + colorReverse + span + colorReverseOff + suffix + colorReset
+}
@@ -1,10 +1,19 @@
 package testastic
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"reflect"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 )
 
 // compare compares expected (from expected file) with actual JSON data.
@@ -13,26 +22,55 @@ import (
 //nolint:funlen // Complex type dispatch is clearer in one function.
 func compare(expected, actual any, path string, cfg *Config) []Difference {
 	if cfg.isFieldIgnored(path) {
+		cfg.Coverage.add(path, true)
+
 		return nil
 	}
 
+	if m, ok := cfg.MatchersAt[path]; ok {
+		expected = m
+	}
+
 	if m, ok := expected.(Matcher); ok {
 		if IsIgnore(m) {
+			cfg.Coverage.add(path, true)
+
+			return nil
+		}
+
+		cfg.Coverage.add(path, false)
+
+		switch cm := m.(type) {
+		case *captureMatcher:
+			cfg.capture(cm.name, actual)
+		case *refMatcher:
+			cfg.addRef(path, cm.name, actual)
+
 			return nil
 		}
 
-		if !m.Match(actual) {
+		matched, reason := matchAt(m, path, actual)
+		if !matched {
 			return []Difference{{
 				Path:     path,
-				Expected: m.String(),
+				Expected: describeMismatch(m, actual),
 				Actual:   actual,
 				Type:     DiffMatcherFailed,
+				Reason:   reason,
 			}}
 		}
 
 		return nil
 	}
 
+	if cfg.LeafValuesOnly && !sameContainerKind(expected, actual) {
+		return nil
+	}
+
+	if isCoverageLeaf(expected) {
+		cfg.Coverage.add(path, false)
+	}
+
 	if expected == nil && actual == nil {
 		return nil
 	}
@@ -84,7 +122,10 @@ func compare(expected, actual any, path string, cfg *Config) []Difference {
 		}}
 
 	case float64:
-		return compareNumbers(exp, actual, path)
+		return compareNumbers(exp, actual, path, cfg)
+
+	case json.Number:
+		return compareJSONNumbers(exp, actual, path, cfg)
 
 	case bool:
 		if act, ok := actual.(bool); ok {
@@ -122,6 +163,30 @@ func compare(expected, actual any, path string, cfg *Config) []Difference {
 	}
 }
 
+// isCoverageLeaf reports whether an expected value counts as a leaf for Coverage:
+// a scalar, null, or an empty object or array.
+func isCoverageLeaf(v any) bool {
+	switch val := v.(type) {
+	case map[string]any:
+		return len(val) == 0
+	case []any:
+		return len(val) == 0
+	default:
+		return true
+	}
+}
+
+// sameContainerKind reports whether neither value is a container or both are containers
+// of the same kind. LeafValuesOnly skips values for which this is false.
+func sameContainerKind(expected, actual any) bool {
+	_, expObj := expected.(map[string]any)
+	_, actObj := actual.(map[string]any)
+	_, expArr := expected.([]any)
+	_, actArr := actual.([]any)
+
+	return expObj == actObj && expArr == actArr
+}
+
 // compareObjects compares two JSON objects (maps).
 func compareObjects(expected map[string]any, actual any, path string, cfg *Config) []Difference {
 	actMap, ok := actual.(map[string]any)
@@ -136,28 +201,90 @@ func compareObjects(expected map[string]any, actual any, path string, cfg *Confi
 
 	var diffs []Difference
 
+	// Actual keys consumed by a FieldAlias, which are not extra fields, and expected
+	// keys whose value was read from their alias instead.
+	aliasedKeys := make(map[string]bool)
+	shadowedKeys := make(map[string]bool)
+
 	// First pass: check for missing and changed keys in expected.
 	for key, expVal := range expected {
 		childPath := path + "." + key
 		if cfg.isFieldIgnored(childPath) {
+			cfg.Coverage.add(childPath, true)
+
 			continue
 		}
 
-		if m, ok := expVal.(Matcher); ok && IsIgnore(m) {
+		union, isUnion := cfg.DiscriminatedUnions[childPath]
+
+		if m, ok := expVal.(Matcher); ok && IsIgnore(m) && !isUnion {
+			cfg.Coverage.add(childPath, true)
+
 			continue
 		}
 
 		actVal, exists := actMap[key]
+
+		// Only a value actually read from the alias key gets the alias note.
+		alias, usedAlias := cfg.FieldAliases[childPath]
+		if usedAlias {
+			var aliasVal any
+
+			aliasVal, usedAlias = actMap[alias]
+			if usedAlias {
+				actVal, exists = aliasVal, true
+				aliasedKeys[alias] = true
+				shadowedKeys[key] = true
+			}
+		}
+
+		if isUnion && exists {
+			if !isUnionPlaceholder(expVal) {
+				diffs = append(diffs, Difference{
+					Path:     childPath,
+					Expected: expVal,
+					Actual:   actVal,
+					Type:     DiffMatcherFailed,
+					Reason:   "discriminated union: the shape file defines this value, expected {{ignore}} or {{anyValue}}",
+				})
+
+				continue
+			}
+
+			diffs = append(diffs, compareDiscriminatedUnion(union, actMap, actVal, childPath, cfg)...)
+
+			continue
+		}
+
+		if !exists && cfg.LeafValuesOnly {
+			continue
+		}
+
+		if !exists && expVal == nil && cfg.TreatNullAsAbsent {
+			cfg.Coverage.add(childPath, false)
+
+			continue
+		}
+
+		var childDiffs []Difference
+
 		if !exists {
-			diffs = append(diffs, Difference{
+			cfg.Coverage.add(childPath, false)
+			childDiffs = []Difference{{
 				Path:     childPath,
 				Expected: expVal,
 				Actual:   nil,
 				Type:     DiffRemoved,
-			})
+			}}
 		} else {
-			diffs = append(diffs, compare(expVal, actVal, childPath, cfg)...)
+			childDiffs = compare(expVal, actVal, childPath, cfg)
 		}
+
+		if usedAlias {
+			childDiffs = withReason(childDiffs, fmt.Sprintf("field alias: %s is read from actual key %q", childPath, alias))
+		}
+
+		diffs = append(diffs, childDiffs...)
 	}
 
 	// Second pass: check for extra keys in actual.
@@ -167,19 +294,176 @@ func compareObjects(expected map[string]any, actual any, path string, cfg *Confi
 			continue
 		}
 
-		if _, exists := expected[key]; !exists {
-			diffs = append(diffs, Difference{
-				Path:     childPath,
-				Expected: nil,
-				Actual:   actVal,
-				Type:     DiffAdded,
-			})
+		// The original key of an alias that was read is not compared, so it is extra.
+		if shadowedKeys[key] {
+			if !cfg.AllowExtraFields && !cfg.LeafValuesOnly && (actVal != nil || !cfg.TreatNullAsAbsent) {
+				diffs = append(diffs, withReason(addedDiffs(actVal, childPath, cfg),
+					fmt.Sprintf("field alias: %s is read from actual key %q", childPath, cfg.FieldAliases[childPath]))...)
+			}
+
+			continue
+		}
+
+		if _, exists := expected[key]; exists || aliasedKeys[key] {
+			continue
 		}
+
+		if union, ok := cfg.DiscriminatedUnions[childPath]; ok {
+			diffs = append(diffs, compareDiscriminatedUnion(union, actMap, actVal, childPath, cfg)...)
+
+			continue
+		}
+
+		if m, ok := cfg.MatchersAt[childPath]; ok {
+			diffs = append(diffs, compare(m, actVal, childPath, cfg)...)
+
+			continue
+		}
+
+		if cfg.AllowExtraFields || cfg.LeafValuesOnly || (actVal == nil && cfg.TreatNullAsAbsent) {
+			continue
+		}
+
+		diffs = append(diffs, addedDiffs(actVal, childPath, cfg)...)
 	}
 
 	return diffs
 }
 
+// addedDiffs reports a value that exists only in actual. With ReportAddedLeaves,
+// objects and arrays are expanded so that each leaf is reported at its full path.
+func addedDiffs(actual any, path string, cfg *Config) []Difference {
+	if cfg.isFieldIgnored(path) {
+		return nil
+	}
+
+	if cfg.ReportAddedLeaves {
+		switch v := actual.(type) {
+		case map[string]any:
+			if len(v) == 0 {
+				break
+			}
+
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			var diffs []Difference
+			for _, key := range keys {
+				diffs = append(diffs, addedDiffs(v[key], path+"."+key, cfg)...)
+			}
+
+			return diffs
+
+		case []any:
+			if len(v) == 0 {
+				break
+			}
+
+			var diffs []Difference
+			for i, item := range v {
+				diffs = append(diffs, addedDiffs(item, fmt.Sprintf("%s[%d]", path, i), cfg)...)
+			}
+
+			return diffs
+		}
+	}
+
+	return []Difference{{
+		Path:     path,
+		Expected: nil,
+		Actual:   actual,
+		Type:     DiffAdded,
+	}}
+}
+
+// compareDiscriminatedUnion compares actual against the expected shape file selected
+// by the discriminator field of the enclosing object.
+func compareDiscriminatedUnion(
+	union DiscriminatedUnion,
+	parent map[string]any,
+	actual any,
+	path string,
+	cfg *Config,
+) []Difference {
+	discriminator, exists := parent[union.Field]
+	if !exists {
+		return []Difference{{
+			Path:     path,
+			Expected: fmt.Sprintf("discriminator field %q", union.Field),
+			Actual:   actual,
+			Type:     DiffMatcherFailed,
+		}}
+	}
+
+	shapeFile, ok := union.Shapes[fmt.Sprint(discriminator)]
+	if !ok {
+		return []Difference{{
+			Path:     path,
+			Expected: fmt.Sprintf("registered shape for %s %v", union.Field, discriminator),
+			Actual:   actual,
+			Type:     DiffMatcherFailed,
+		}}
+	}
+
+	shape, err := cfg.shape(shapeFile)
+	if err != nil {
+		return []Difference{{
+			Path:     path,
+			Expected: fmt.Sprintf("shape %s: %v", shapeFile, err),
+			Actual:   actual,
+			Type:     DiffMatcherFailed,
+		}}
+	}
+
+	// Unordered arrays in the shape file are rooted at the union path.
+	for _, p := range shape.UnorderedPaths {
+		rebased := path + strings.TrimPrefix(p, "$")
+		if !slices.Contains(cfg.IgnoreArrayOrderPaths, rebased) {
+			cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, rebased)
+		}
+	}
+
+	return compare(shape.Data, actual, path, cfg)
+}
+
+// parsedShape is a parsed discriminated union shape file or the error parsing it.
+type parsedShape struct {
+	expected *ExpectedJSON
+	err      error
+}
+
+// shape returns the parsed shape file, reading it only on first use.
+func (c *Config) shape(file string) (*ExpectedJSON, error) {
+	if s, ok := c.shapes[file]; ok {
+		return s.expected, s.err
+	}
+
+	expected, err := parseExpectedFile(file, c.useNumber())
+
+	if c.shapes == nil {
+		c.shapes = make(map[string]parsedShape)
+	}
+
+	c.shapes[file] = parsedShape{expected: expected, err: err}
+
+	return expected, err
+}
+
+// isUnionPlaceholder reports whether an expected value at a discriminated union path
+// defers to the shape file.
+func isUnionPlaceholder(expected any) bool {
+	switch expected.(type) {
+	case ignoreMatcher, anyValueMatcher:
+		return true
+	default:
+		return false
+	}
+}
+
 // compareArrays compares two JSON arrays.
 func compareArrays(expected []any, actual any, path string, cfg *Config) []Difference {
 	actArr, ok := actual.([]any)
@@ -196,25 +480,87 @@ func compareArrays(expected []any, actual any, path string, cfg *Config) []Diffe
 		return compareArraysUnordered(expected, actArr, path, cfg)
 	}
 
+	if key, ok := cfg.SortArrayKeys[path]; ok {
+		expected = sortArrayByKey(expected, key)
+		actArr = sortArrayByKey(actArr, key)
+	}
+
 	return compareArraysOrdered(expected, actArr, path, cfg)
 }
 
+// sortArrayByKey returns a copy of items stably sorted by the value of key in each
+// object. Numbers sort before strings; items without a sortable key value, such as
+// non-objects, missing keys or matchers, keep their order after the sorted ones.
+func sortArrayByKey(items []any, key string) []any {
+	sorted := slices.Clone(items)
+
+	slices.SortStableFunc(sorted, func(a, b any) int {
+		aRank, aNum, aStr := arraySortKey(a, key)
+		bRank, bNum, bStr := arraySortKey(b, key)
+
+		switch {
+		case aRank != bRank:
+			return cmp.Compare(aRank, bRank)
+		case aRank == sortRankNumber:
+			return cmp.Compare(aNum, bNum)
+		case aRank == sortRankString:
+			return strings.Compare(aStr, bStr)
+		default:
+			return 0
+		}
+	})
+
+	return sorted
+}
+
+// Sort ranks for sortArrayByKey.
+const (
+	sortRankNumber = iota
+	sortRankString
+	sortRankMissing
+)
+
+// arraySortKey extracts the sort rank and value of key from an array item.
+func arraySortKey(item any, key string) (int, float64, string) {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return sortRankMissing, 0, ""
+	}
+
+	switch v := obj[key].(type) {
+	case float64:
+		return sortRankNumber, v, ""
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return sortRankMissing, 0, ""
+		}
+
+		return sortRankNumber, f, ""
+	case string:
+		return sortRankString, 0, v
+	default:
+		return sortRankMissing, 0, ""
+	}
+}
+
 // compareArraysOrdered compares arrays where order matters.
 func compareArraysOrdered(expected, actual []any, path string, cfg *Config) []Difference {
 	var diffs []Difference
 
-	for i := range max(len(expected), len(actual)) {
+	n := max(len(expected), len(actual))
+	if cfg.LeafValuesOnly {
+		n = min(len(expected), len(actual))
+	}
+
+	for i := range n {
 		childPath := fmt.Sprintf("%s[%d]", path, i)
 
 		switch {
 		case i >= len(expected):
-			diffs = append(diffs, Difference{
-				Path:     childPath,
-				Expected: nil,
-				Actual:   actual[i],
-				Type:     DiffAdded,
-			})
+			diffs = append(diffs, addedDiffs(actual[i], childPath, cfg)...)
 		case i >= len(actual):
+			cfg.Coverage.add(childPath, false)
 			diffs = append(diffs, Difference{
 				Path:     childPath,
 				Expected: expected[i],
@@ -229,32 +575,30 @@ func compareArraysOrdered(expected, actual []any, path string, cfg *Config) []Di
 	return diffs
 }
 
-// compareArraysUnordered compares arrays where order doesn't matter.
+// compareArraysUnordered compares arrays where order doesn't matter. Elements that
+// match exactly are paired first; the remaining ones are then paired by fewest
+// differences. Expected elements left without a partner are reported as removed and
+// unused actual elements as added.
 //
 //nolint:funlen // Unordered comparison requires explicit matching logic.
 func compareArraysUnordered(expected, actual []any, path string, cfg *Config) []Difference {
-	if len(expected) != len(actual) {
-		return []Difference{{
-			Path:     path,
-			Expected: fmt.Sprintf("array of length %d", len(expected)),
-			Actual:   fmt.Sprintf("array of length %d", len(actual)),
-			Type:     DiffChanged,
-		}}
-	}
-
 	used := make([]bool, len(actual))
 
 	var unmatched []int
 
 	for i, exp := range expected {
 		found := false
+		childPath := fmt.Sprintf("%s[%d]", path, i)
 
 		for j, act := range actual {
 			if used[j] {
 				continue
 			}
 
-			if len(compare(exp, act, path, cfg)) == 0 {
+			if tryCompare(exp, act, childPath, cfg) == 0 {
+				// Repeat the comparison to keep its captures and refs.
+				compare(exp, act, childPath, cfg)
+
 				used[j] = true
 				found = true
 
@@ -267,41 +611,94 @@ func compareArraysUnordered(expected, actual []any, path string, cfg *Config) []
 		}
 	}
 
-	if len(unmatched) > 0 {
-		var unusedActual []int
+	// Score every unmatched expected element against every unused actual element.
+	scores := make(map[[2]int]int)
 
-		for i, u := range used {
-			if !u {
-				unusedActual = append(unusedActual, i)
+	for _, i := range unmatched {
+		for j, act := range actual {
+			if !used[j] {
+				scores[[2]int{i, j}] = tryCompare(expected[i], act, fmt.Sprintf("%s[%d]", path, i), cfg)
 			}
 		}
+	}
 
-		var diffs []Difference
-
-		for i, idx := range unmatched {
-			childPath := fmt.Sprintf("%s[%d]", path, idx)
+	var diffs []Difference
 
-			var actualVal any
-			if i < len(unusedActual) {
-				actualVal = actual[unusedActual[i]]
+	// Repeatedly pair the expected and actual elements that differ least, and report
+	// their field-level differences with the pairing as reason. Only the comparison of
+	// a chosen pair keeps its captures and refs.
+	for len(unmatched) > 0 {
+		bestI, best, bestScore := -1, -1, 0
+
+		for k, i := range unmatched {
+			for j := range actual {
+				if used[j] {
+					continue
+				}
+
+				if score := scores[[2]int{i, j}]; best < 0 || score < bestScore {
+					bestI, best, bestScore = k, j, score
+				}
 			}
+		}
 
-			diffs = append(diffs, Difference{
-				Path:     childPath,
-				Expected: expected[idx],
-				Actual:   actualVal,
-				Type:     DiffChanged,
-			})
+		// All actual elements are paired.
+		if best < 0 {
+			break
 		}
 
+		idx := unmatched[bestI]
+		unmatched = slices.Delete(unmatched, bestI, bestI+1)
+		used[best] = true
+
+		childPath := fmt.Sprintf("%s[%d]", path, idx)
+		reason := fmt.Sprintf("unordered: expected element %s best matches actual index %d", childPath, best)
+		diffs = append(diffs, withReason(compare(expected[idx], actual[best], childPath, cfg), reason)...)
+	}
+
+	if cfg.LeafValuesOnly {
 		return diffs
 	}
 
-	return nil
+	// Expected has more elements than actual: report the ones left unpaired.
+	for _, idx := range unmatched {
+		childPath := fmt.Sprintf("%s[%d]", path, idx)
+		cfg.Coverage.add(childPath, false)
+		diffs = append(diffs, Difference{
+			Path:     childPath,
+			Expected: expected[idx],
+			Actual:   nil,
+			Type:     DiffRemoved,
+			Reason:   "unordered: no actual element is left to pair with expected element " + childPath,
+		})
+	}
+
+	// Actual has more elements than expected: report the ones left unpaired.
+	for j, act := range actual {
+		if !used[j] {
+			reason := fmt.Sprintf("unordered: actual index %d is not paired with an expected element", j)
+			diffs = append(diffs, withReason(addedDiffs(act, fmt.Sprintf("%s[%d]", path, j), cfg), reason)...)
+		}
+	}
+
+	return diffs
+}
+
+// withReason prefixes reason to the Reason of each difference.
+func withReason(diffs []Difference, reason string) []Difference {
+	for i, d := range diffs {
+		if d.Reason == "" {
+			diffs[i].Reason = reason
+		} else {
+			diffs[i].Reason = reason + "; " + d.Reason
+		}
+	}
+
+	return diffs
 }
 
 // compareNumbers compares numeric values, handling JSON number quirks.
-func compareNumbers(expected float64, actual any, path string) []Difference {
+func compareNumbers(expected float64, actual any, path string, cfg *Config) []Difference {
 	var actNum float64
 
 	switch v := actual.(type) {
@@ -315,6 +712,18 @@ func compareNumbers(expected float64, actual any, path string) []Difference {
 		actNum = float64(v)
 	case int32:
 		actNum = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return []Difference{{
+				Path:     path,
+				Expected: expected,
+				Actual:   actual,
+				Type:     DiffTypeMismatch,
+			}}
+		}
+
+		actNum = f
 	default:
 		return []Difference{{
 			Path:     path,
@@ -324,7 +733,8 @@ func compareNumbers(expected float64, actual any, path string) []Difference {
 		}}
 	}
 
-	if expected != actNum {
+	tolerance, _ := cfg.toleranceAt(path)
+	if !numbersEqual(expected, actNum, tolerance) && !cfg.significantFiguresEqual(path, expected, actNum) {
 		return []Difference{{
 			Path:     path,
 			Expected: expected,
@@ -336,11 +746,123 @@ func compareNumbers(expected float64, actual any, path string) []Difference {
 	return nil
 }
 
-// parseActualJSON converts the actual value to a comparable JSON structure.
-func parseActualJSON(data []byte) (any, error) {
+// compareJSONNumbers compares numbers parsed as json.Number.
+// With StrictNumberTypes, integer and decimal representations are a type mismatch.
+func compareJSONNumbers(expected json.Number, actual any, path string, cfg *Config) []Difference {
+	act, ok := actual.(json.Number)
+	if !ok {
+		expNum, err := expected.Float64()
+		if err != nil {
+			return []Difference{{
+				Path:     path,
+				Expected: expected,
+				Actual:   actual,
+				Type:     DiffTypeMismatch,
+			}}
+		}
+
+		return compareNumbers(expNum, actual, path, cfg)
+	}
+
+	if cfg.StrictNumberTypes && isIntegerLiteral(expected) != isIntegerLiteral(act) {
+		return []Difference{{
+			Path:     path,
+			Expected: expected,
+			Actual:   act,
+			Type:     DiffTypeMismatch,
+		}}
+	}
+
+	tolerance, _ := cfg.toleranceAt(path)
+	if !jsonNumbersEqual(expected, act, tolerance) && !jsonNumbersSignificantEqual(expected, act, path, cfg) {
+		return []Difference{{
+			Path:     path,
+			Expected: expected,
+			Actual:   act,
+			Type:     DiffChanged,
+		}}
+	}
+
+	return nil
+}
+
+// numbersEqual reports whether two numbers differ by at most tolerance.
+func numbersEqual(expected, actual, tolerance float64) bool {
+	return expected == actual || math.Abs(expected-actual) <= tolerance
+}
+
+// jsonNumbersEqual reports whether two json.Number values differ by at most tolerance.
+// Values are compared as exact rationals, so large integers beyond 2^53 keep their precision.
+func jsonNumbersEqual(expected, actual json.Number, tolerance float64) bool {
+	exp, expOK := new(big.Rat).SetString(expected.String())
+	act, actOK := new(big.Rat).SetString(actual.String())
+
+	if !expOK || !actOK {
+		return false
+	}
+
+	if exp.Cmp(act) == 0 {
+		return true
+	}
+
+	tol := new(big.Rat)
+	if tol.SetFloat64(tolerance) == nil {
+		return false
+	}
+
+	diff := new(big.Rat).Sub(exp, act)
+
+	return diff.Abs(diff).Cmp(tol) <= 0
+}
+
+// jsonNumbersSignificantEqual applies SignificantFigures to json.Number values.
+func jsonNumbersSignificantEqual(expected, actual json.Number, path string, cfg *Config) bool {
+	exp, expErr := expected.Float64()
+	act, actErr := actual.Float64()
+
+	return expErr == nil && actErr == nil && cfg.significantFiguresEqual(path, exp, act)
+}
+
+// isIntegerLiteral reports whether a JSON number is written without a fraction or exponent.
+func isIntegerLiteral(n json.Number) bool {
+	return !strings.ContainsAny(n.String(), ".eE")
+}
+
+// ErrTrailingJSONData is returned when JSON input contains data after the top-level value.
+var ErrTrailingJSONData = errors.New("unexpected data after top-level JSON value")
+
+// decodeJSON parses JSON data, optionally preserving numbers as json.Number.
+func decodeJSON(data []byte, useNumber bool) (any, error) {
 	var result any
 
-	err := json.Unmarshal(data, &result)
+	if !useNumber {
+		err := json.Unmarshal(data, &result)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		return result, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	err := dec.Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	_, err = dec.Token()
+	if !errors.Is(err, io.EOF) {
+		return nil, ErrTrailingJSONData
+	}
+
+	return result, nil
+}
+
+// parseActualJSON converts the actual value to a comparable JSON structure.
+func parseActualJSON(data []byte, useNumber bool) (any, error) {
+	result, err := decodeJSON(data, useNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse actual JSON: %w", err)
 	}
@@ -354,3 +876,79 @@ func sortDiffs(diffs []Difference) {
 		return diffs[i].Path < diffs[j].Path
 	})
 }
+
+// valueAtPath returns the value at a JSON path like $.users[0].name.
+func valueAtPath(data any, path string) (any, bool) {
+	value, missing := lookupPath(data, path)
+
+	return value, missing == ""
+}
+
+// lookupPath returns the value at a JSON path like $.users[0].name. If the path does
+// not resolve, it returns the prefix of path up to the first segment that is missing,
+// e.g. $.users[5] for a list of two users.
+func lookupPath(data any, path string) (any, string) {
+	segments := splitPath(path)
+	if len(segments) == 0 || segments[0] != "$" {
+		return nil, path
+	}
+
+	current := data
+	resolved := "$"
+
+	for _, seg := range segments[1:] {
+		resolved += seg
+
+		switch {
+		case strings.HasPrefix(seg, "."):
+			obj, ok := current.(map[string]any)
+			if !ok {
+				return nil, resolved
+			}
+
+			current, ok = obj[seg[1:]]
+			if !ok {
+				return nil, resolved
+			}
+
+		case strings.HasPrefix(seg, "[") && strings.HasSuffix(seg, "]"):
+			arr, ok := current.([]any)
+			if !ok {
+				return nil, resolved
+			}
+
+			idx, err := strconv.Atoi(seg[1 : len(seg)-1])
+			if err != nil || idx < 0 || idx >= len(arr) {
+				return nil, resolved
+			}
+
+			current = arr[idx]
+
+		default:
+			return nil, resolved
+		}
+	}
+
+	return current, ""
+}
+
+// splitPath splits a JSON path into its segments.
+// For example, "$.users[0].name" becomes ["$", ".users", "[0]", ".name"].
+func splitPath(path string) []string {
+	var segments []string
+
+	start := 0
+
+	for i := 1; i < len(path); i++ {
+		if path[i] == '.' || path[i] == '[' {
+			segments = append(segments, path[start:i])
+			start = i
+		}
+	}
+
+	if path != "" {
+		segments = append(segments, path[start:])
+	}
+
+	return segments
+}
@@ -3,6 +3,7 @@ package testastic
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -26,6 +27,10 @@ const (
 // maxDisplayLineLen is the maximum length for displaying values before truncation.
 const maxDisplayLineLen = 80
 
+// floatDisplayDigits is the number of significant digits floats are displayed with,
+// so that 0.1+0.2 shows as 0.3 instead of 0.30000000000000004.
+const floatDisplayDigits = 12
+
 // String returns a human-readable description of the diff type.
 func (d DiffType) String() string {
 	switch d {
@@ -50,6 +55,29 @@ type Difference struct {
 	Expected any      // Expected value (or matcher description)
 	Actual   any      // Actual value
 	Type     DiffType // Type of difference
+	Reason   string   // Why a matcher rejected the value, or a PathMatcher's reason, if any
+}
+
+// DiffStats summarizes a set of differences.
+type DiffStats struct {
+	Total    int              // Total number of differences
+	ByType   map[DiffType]int // Number of differences per type
+	MaxDepth int              // Deepest path nesting among the differences, e.g. 2 for "$.user.name"
+}
+
+// NewDiffStats computes summary statistics for the given differences.
+func NewDiffStats(diffs []Difference) DiffStats {
+	stats := DiffStats{
+		Total:  len(diffs),
+		ByType: make(map[DiffType]int),
+	}
+
+	for _, d := range diffs {
+		stats.ByType[d.Type]++
+		stats.MaxDepth = max(stats.MaxDepth, len(splitPath(d.Path))-1)
+	}
+
+	return stats
 }
 
 // FormatDiff formats a slice of differences into a human-readable string.
@@ -69,6 +97,8 @@ func FormatDiff(diffs []Difference) string {
 		sb.WriteString(fmt.Sprintf("JSON mismatch at %d paths:\n", len(diffs)))
 	}
 
+	sb.WriteString(formatDiffSummary(NewDiffStats(diffs).ByType))
+
 	for _, d := range diffs {
 		sb.WriteString("\n")
 		sb.WriteString(fmt.Sprintf("  %s\n", d.Path))
@@ -87,17 +117,89 @@ func FormatDiff(diffs []Difference) string {
 			sb.WriteString(fmt.Sprintf("    actual:   %s (%s)\n", formatValue(d.Actual), typeOf(d.Actual)))
 
 		case DiffChanged, DiffMatcherFailed:
-			sb.WriteString(fmt.Sprintf("    expected: %s\n", formatValue(d.Expected)))
-			sb.WriteString(fmt.Sprintf("    actual:   %s\n", formatValue(d.Actual)))
+			expected, actual := formatValuePair(d.Expected, d.Actual)
+			sb.WriteString(fmt.Sprintf("    expected: %s\n", expected))
+			sb.WriteString(fmt.Sprintf("    actual:   %s\n", actual))
+		}
+
+		if d.Reason != "" {
+			sb.WriteString(fmt.Sprintf("    reason:   %s\n", d.Reason))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatDiffSummary formats the number of differences per type as a line such as
+// "  3 changed, 1 added, 2 removed".
+func formatDiffSummary(counts map[DiffType]int) string {
+	parts := make([]string, 0, len(counts))
+
+	for _, t := range []DiffType{DiffChanged, DiffAdded, DiffRemoved, DiffTypeMismatch, DiffMatcherFailed} {
+		if counts[t] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[t], t))
+		}
+	}
+
+	return "  " + strings.Join(parts, ", ") + "\n"
+}
+
+// formatReasons lists the custom failure reasons of the differences, if any.
+// It complements FormatDiffInline, which shows values but not reasons.
+func formatReasons(diffs []Difference) string {
+	var sb strings.Builder
+
+	for _, d := range diffs {
+		if d.Reason != "" {
+			sb.WriteString(fmt.Sprintf("\n  %s\n    reason: %s\n", d.Path, d.Reason))
 		}
 	}
 
 	return sb.String()
 }
 
+// jsonDifference is the machine-readable form of a Difference used by FormatDiffJSON.
+type jsonDifference struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// FormatDiffJSON formats differences as a JSON array of objects with path, type,
+// expected, actual and, if present, reason fields, e.g. for CI reporting.
+// Matchers in expected values are serialized as their template expression.
+func FormatDiffJSON(diffs []Difference) (string, error) {
+	out := make([]jsonDifference, len(diffs))
+
+	for i, d := range diffs {
+		out[i] = jsonDifference{
+			Path:     d.Path,
+			Type:     d.Type.String(),
+			Expected: cleanMatchersForDisplay(d.Expected),
+			Actual:   cleanMatchersForDisplay(d.Actual),
+			Reason:   d.Reason,
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal diffs: %w", err)
+	}
+
+	return string(data), nil
+}
+
 // FormatDiffInline generates a git-style inline diff between expected and actual JSON.
 // Shows the full JSON with - prefix for removed lines and + prefix for added lines.
 func FormatDiffInline(expected, actual any) string {
+	return formatDiffInline(expected, actual, -1)
+}
+
+// formatDiffInline is FormatDiffInline with unchanged lines collapsed to contextLines
+// around each change. A negative contextLines shows the full JSON.
+func formatDiffInline(expected, actual any, contextLines int) string {
 	expClean := cleanMatchersForDisplay(expected)
 	actClean := cleanMatchersForDisplay(actual)
 
@@ -113,7 +215,7 @@ func FormatDiffInline(expected, actual any) string {
 
 	expLines := strings.Split(string(expJSON), "\n")
 	actLines := strings.Split(string(actJSON), "\n")
-	diff := computeDiff(expLines, actLines)
+	diff := computeDiffContext(expLines, actLines, contextLines)
 
 	var sb strings.Builder
 
@@ -134,11 +236,181 @@ const (
 	diffInsert
 )
 
+// diffLine is a single line of a computed diff with its operation.
+type diffLine struct {
+	op   diffOp
+	line string
+}
+
 // computeDiff generates a unified diff between two sets of lines.
-// Uses a simple LCS-based algorithm for readability.
-//
-//nolint:funlen // LCS algorithm requires sequential steps.
+// Changed lines with the same key are paired so only the changed span is highlighted.
 func computeDiff(expected, actual []string) []string {
+	return computeDiffContext(expected, actual, -1)
+}
+
+// computeDiffContext is computeDiff with runs of unchanged lines farther than
+// contextLines from a change collapsed into an "@@ n unchanged lines @@" separator.
+// A negative contextLines keeps all lines.
+func computeDiffContext(expected, actual []string, contextLines int) []string {
+	ops := diffLines(expected, actual)
+	keep := contextMask(ops, contextLines)
+	result := make([]string, 0, len(ops))
+
+	for k := 0; k < len(ops); {
+		if keep != nil && !keep[k] {
+			skipped := 0
+			for k < len(ops) && !keep[k] {
+				skipped++
+				k++
+			}
+
+			result = append(result, fmt.Sprintf("@@ %d unchanged lines @@", skipped))
+
+			continue
+		}
+
+		if ops[k].op != diffDelete {
+			result = append(result, formatDiffLine(ops[k]))
+			k++
+
+			continue
+		}
+
+		// Collect a run of deletions followed by insertions.
+		delEnd := k
+		for delEnd < len(ops) && ops[delEnd].op == diffDelete {
+			delEnd++
+		}
+
+		insEnd := delEnd
+		for insEnd < len(ops) && ops[insEnd].op == diffInsert {
+			insEnd++
+		}
+
+		result = append(result, formatChangeBlock(ops[k:delEnd], ops[delEnd:insEnd])...)
+		k = insEnd
+	}
+
+	return result
+}
+
+// contextMask marks the lines within contextLines of a change. It returns nil, meaning
+// every line is kept, for a negative contextLines. Changed lines are always kept.
+func contextMask(ops []diffLine, contextLines int) []bool {
+	if contextLines < 0 {
+		return nil
+	}
+
+	keep := make([]bool, len(ops))
+
+	for i, op := range ops {
+		if op.op == diffEqual {
+			continue
+		}
+
+		for j := max(0, i-contextLines); j <= min(len(ops)-1, i+contextLines); j++ {
+			keep[j] = true
+		}
+	}
+
+	return keep
+}
+
+// formatDiffLine formats a single diff line with its prefix and color.
+func formatDiffLine(op diffLine) string {
+	switch op.op {
+	case diffDelete:
+		return red("- " + op.line)
+	case diffInsert:
+		return green("+ " + op.line)
+	default:
+		return "  " + op.line
+	}
+}
+
+// formatChangeBlock formats removed lines followed by added lines. When each removed
+// line pairs with an added line for the same key, only the changed span is highlighted.
+func formatChangeBlock(dels, ins []diffLine) []string {
+	result := make([]string, 0, len(dels)+len(ins))
+
+	if !pairsByKey(dels, ins) {
+		for _, op := range dels {
+			result = append(result, formatDiffLine(op))
+		}
+
+		for _, op := range ins {
+			result = append(result, formatDiffLine(op))
+		}
+
+		return result
+	}
+
+	added := make([]string, 0, len(ins))
+
+	for i := range dels {
+		exp, act := []rune(dels[i].line), []rune(ins[i].line)
+		prefix, suffix := commonAffixes(exp, act)
+
+		result = append(result, colorizeSpan(
+			"- "+string(exp[:prefix]), string(exp[prefix:len(exp)-suffix]), string(exp[len(exp)-suffix:]), colorRed,
+		))
+		added = append(added, colorizeSpan(
+			"+ "+string(act[:prefix]), string(act[prefix:len(act)-suffix]), string(act[len(act)-suffix:]), colorGreen,
+		))
+	}
+
+	return append(result, added...)
+}
+
+// pairsByKey reports whether removed and added lines pair up one-to-one by key.
+func pairsByKey(dels, ins []diffLine) bool {
+	if len(dels) == 0 || len(dels) != len(ins) {
+		return false
+	}
+
+	for i := range dels {
+		if lineKey(dels[i].line) != lineKey(ins[i].line) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lineKey returns the indentation and object key of a formatted line, e.g. `  "name": `,
+// which identifies the JSON path of the line within a change block.
+func lineKey(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(trimmed)]
+
+	if strings.HasPrefix(trimmed, `"`) {
+		if idx := strings.Index(trimmed, `": `); idx > 0 {
+			return indent + trimmed[:idx+3]
+		}
+	}
+
+	return indent
+}
+
+// commonAffixes returns the lengths of the common prefix and suffix of a and b.
+// The suffix never overlaps the prefix.
+func commonAffixes(a, b []rune) (int, int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	return prefix, suffix
+}
+
+// diffLines computes the diff operations between two sets of lines.
+// Uses a simple LCS-based algorithm for readability.
+func diffLines(expected, actual []string) []diffLine {
 	// Build the longest common subsequence (LCS) matrix.
 	m, n := len(expected), len(actual)
 
@@ -158,52 +430,28 @@ func computeDiff(expected, actual []string) []string {
 	}
 
 	// Backtrack through LCS matrix to build diff operations.
-	var result []string
-
 	i, j := m, n
 
-	var ops []struct {
-		op   diffOp
-		line string
-	}
+	var ops []diffLine
 
 	for i > 0 || j > 0 {
 		switch {
 		case i > 0 && j > 0 && expected[i-1] == actual[j-1]:
-			ops = append(ops, struct {
-				op   diffOp
-				line string
-			}{diffEqual, expected[i-1]})
+			ops = append(ops, diffLine{diffEqual, expected[i-1]})
 			i--
 			j--
 		case j > 0 && (i == 0 || dp[i][j-1] >= dp[i-1][j]):
-			ops = append(ops, struct {
-				op   diffOp
-				line string
-			}{diffInsert, actual[j-1]})
+			ops = append(ops, diffLine{diffInsert, actual[j-1]})
 			j--
 		case i > 0:
-			ops = append(ops, struct {
-				op   diffOp
-				line string
-			}{diffDelete, expected[i-1]})
+			ops = append(ops, diffLine{diffDelete, expected[i-1]})
 			i--
 		}
 	}
 
-	for k := len(ops) - 1; k >= 0; k-- {
-		op := ops[k]
-		switch op.op {
-		case diffEqual:
-			result = append(result, "  "+op.line)
-		case diffDelete:
-			result = append(result, red("- "+op.line))
-		case diffInsert:
-			result = append(result, green("+ "+op.line))
-		}
-	}
+	slices.Reverse(ops)
 
-	return result
+	return ops
 }
 
 // cleanMatchersForDisplay converts Matcher objects to their string representation
@@ -234,6 +482,22 @@ func cleanMatchersForDisplay(data any) any {
 	}
 }
 
+// formatValuePair formats an expected and actual value. Floats that differ only
+// beyond the display precision are shown with full precision, so the difference
+// stays visible.
+func formatValuePair(expected, actual any) (string, string) {
+	exp, act := formatValue(expected), formatValue(actual)
+
+	expFloat, expOK := expected.(float64)
+	actFloat, actOK := actual.(float64)
+
+	if expOK && actOK && exp == act && expFloat != actFloat {
+		return strconv.FormatFloat(expFloat, 'g', -1, 64), strconv.FormatFloat(actFloat, 'g', -1, 64)
+	}
+
+	return exp, act
+}
+
 // formatValue formats a value for display in diff output.
 func formatValue(v any) string {
 	if v == nil {
@@ -250,7 +514,7 @@ func formatValue(v any) string {
 			return strconv.FormatInt(int64(val), 10)
 		}
 
-		return fmt.Sprintf("%g", val)
+		return strconv.FormatFloat(val, 'g', floatDisplayDigits, 64)
 
 	case bool:
 		return strconv.FormatBool(val)
@@ -285,7 +549,7 @@ func typeOf(v any) string {
 	switch v.(type) {
 	case string:
 		return "string"
-	case float64:
+	case float64, json.Number:
 		return "number"
 	case bool:
 		return "boolean"
@@ -0,0 +1,62 @@
+package testastic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// AssertForm compares form-encoded data (application/x-www-form-urlencoded)
+// against an expected JSON file. The form is compared as an object mapping each
+// key to its array of values, so matchers can be used on individual values.
+// T can be: url.Values, []byte, string, or io.Reader.
+//
+// Example expected file:
+//
+//	{"name": ["Alice"], "token": ["{{anyString}}"]}
+func AssertForm[T any](tb Reporter, expectedFile string, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := formToJSON(actual)
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	assertJSONFile(tb, "AssertForm", expectedFile, actualBytes, newConfig(opts...))
+}
+
+// formToJSON parses form-encoded data and converts it to JSON.
+func formToJSON[T any](v T) ([]byte, error) {
+	values, ok := any(v).(url.Values)
+	if !ok {
+		raw, err := toBytes(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert actual to bytes: %w", err)
+		}
+
+		values, err = url.ParseQuery(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse form data: %w", err)
+		}
+	}
+
+	form := make(map[string][]any, len(values))
+
+	for key, vals := range values {
+		items := make([]any, len(vals))
+		for i, val := range vals {
+			items[i] = val
+		}
+
+		form[key] = items
+	}
+
+	data, err := json.Marshal(form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal form data: %w", err)
+	}
+
+	return data, nil
+}
@@ -2,9 +2,10 @@ module github.com/monkescience/testastic
 
 go 1.25.5
 
-require golang.org/x/term v0.38.0
-
 require (
-	golang.org/x/net v0.48.0 // indirect
-	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/net v0.48.0
+	golang.org/x/term v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/sys v0.39.0 // indirect
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"testing"
+	"strings"
 )
 
 // ErrUnsupportedHTMLType is returned when an unsupported type is passed to AssertHTML.
 var ErrUnsupportedHTMLType = errors.New("unsupported type for HTML comparison")
 
+// ErrInvalidHTMLFragment is returned when an expected fragment does not have a single root element.
+var ErrInvalidHTMLFragment = errors.New("expected HTML fragment must have a single root element")
+
 // AssertHTML compares actual HTML against an expected HTML file.
 // T can be: []byte, string, io.Reader, or any type implementing fmt.Stringer.
 //
@@ -21,7 +24,7 @@ var ErrUnsupportedHTMLType = errors.New("unsupported type for HTML comparison")
 //	testastic.AssertHTML(t, "testdata/user.expected.html", htmlString)
 //
 //nolint:funlen // Main assertion function needs sequential validation steps.
-func AssertHTML[T any](tb testing.TB, expectedFile string, actual T, opts ...HTMLOption) {
+func AssertHTML[T any](tb Reporter, expectedFile string, actual T, opts ...HTMLOption) {
 	tb.Helper()
 
 	// Convert actual to []byte
@@ -34,6 +37,23 @@ func AssertHTML[T any](tb testing.TB, expectedFile string, actual T, opts ...HTM
 
 	// Build config
 	cfg := newHTMLConfig(opts...)
+	if cfg.err != nil {
+		tb.Fatalf("testastic: %v", cfg.err)
+
+		return
+	}
+
+	if cfg.Record {
+		recordErr := createExpectedHTMLFile(expectedFile, actualBytes)
+		if recordErr != nil {
+			tb.Fatalf("testastic: failed to record expected HTML file: %v", recordErr)
+		}
+
+		recordUpdate("recorded", expectedFile)
+		tb.Logf("testastic: recorded expected HTML file %s", expectedFile)
+
+		return
+	}
 
 	// Check if expected file exists
 	_, statErr := os.Stat(expectedFile)
@@ -44,6 +64,7 @@ func AssertHTML[T any](tb testing.TB, expectedFile string, actual T, opts ...HTM
 				tb.Fatalf("testastic: failed to create expected HTML file: %v", createErr)
 			}
 
+			recordUpdate("created", expectedFile)
 			tb.Logf("testastic: created expected HTML file %s", expectedFile)
 
 			return
@@ -65,17 +86,14 @@ func AssertHTML[T any](tb testing.TB, expectedFile string, actual T, opts ...HTM
 		return
 	}
 
-	// Parse actual HTML
-	actualNode, err := parseActualHTMLBytes(actualBytes)
+	// Parse actual HTML and compare
+	actualNode, diffs, err := compareHTMLDocuments(expected, actualBytes, cfg)
 	if err != nil {
 		tb.Fatalf("testastic: %v", err)
 
 		return
 	}
 
-	// Compare
-	diffs := compareHTML(expected.Root, actualNode, cfg)
-
 	// If update mode and there are differences, update the file
 	if cfg.Update && len(diffs) > 0 {
 		updateErr := updateExpectedHTMLFile(expectedFile, actualBytes)
@@ -83,19 +101,251 @@ func AssertHTML[T any](tb testing.TB, expectedFile string, actual T, opts ...HTM
 			tb.Fatalf("testastic: failed to update expected HTML file: %v", updateErr)
 		}
 
+		recordUpdate("updated", expectedFile)
 		tb.Logf("testastic: updated expected HTML file %s", expectedFile)
 
+		// Updating the expected file cannot add attributes missing from actual.
+		if missing := missingRequiredAttributes(actualNode, cfg); len(missing) > 0 {
+			tb.Errorf("testastic: assertion failed\n\n  AssertHTML (%s)\n%s", expectedFile, formatHTMLReasons(missing))
+		}
+
 		return
 	}
 
 	// Report differences
 	if len(diffs) > 0 {
-		sortHTMLDiffs(diffs)
 		tb.Errorf(
-			"testastic: assertion failed\n\n  AssertHTML (%s)\n%s",
-			expectedFile, FormatHTMLDiffInline(expected.Root, actualNode),
+			"testastic: assertion failed\n\n  AssertHTML (%s)\n%s%s",
+			expectedFile, FormatHTMLDiffInline(expected.Root, actualNode), formatHTMLReasons(diffs),
+		)
+	}
+}
+
+// AssertHTMLContains asserts that the HTML fragment in expectedFile appears somewhere
+// in actual, regardless of the surrounding markup. The fragment must have a single root
+// element and may contain matchers. On failure, the closest candidate is shown.
+// Expected fragment files are not created or updated automatically.
+//
+// Example:
+//
+//	testastic.AssertHTMLContains(t, "testdata/alert.fragment.html", resp.Body)
+func AssertHTMLContains[T any](tb Reporter, expectedFile string, actual T, opts ...HTMLOption) {
+	tb.Helper()
+
+	actualBytes, err := toHTMLBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newHTMLConfig(opts...)
+	if cfg.err != nil {
+		tb.Fatalf("testastic: %v", cfg.err)
+
+		return
+	}
+
+	expected, err := ParseExpectedHTMLFile(expectedFile)
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	fragment, err := fragmentRoot(expected.Root, cfg)
+	if err != nil {
+		tb.Fatalf("testastic: %v: %s", err, expectedFile)
+
+		return
+	}
+
+	actualNode, err := parseActualHTMLBytes(actualBytes)
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	closest, diffs := findHTMLFragment(fragment, actualNode, cfg)
+	if closest != nil && len(diffs) == 0 {
+		return
+	}
+
+	if closest == nil {
+		tb.Errorf(
+			"testastic: assertion failed\n\n  AssertHTMLContains (%s)\n    no matching fragment found: actual has no elements",
+			expectedFile,
 		)
+
+		return
 	}
+
+	sortHTMLDiffs(diffs)
+	tb.Errorf(
+		"testastic: assertion failed\n\n  AssertHTMLContains (%s)\n    no matching fragment found, closest candidate at %s:\n%s%s",
+		expectedFile, closest.Path, FormatHTMLDiffInline(fragment, closest), formatHTMLReasons(diffs),
+	)
+}
+
+// AssertVisible asserts that every element matching selector in actual is visible.
+// An element is hidden if it or an ancestor has the hidden attribute,
+// aria-hidden="true", or an inline style with display:none.
+// The selector supports tag names, #id, .class, [attr] and [attr=value], joined by
+// descendant (space) and child (>) combinators.
+//
+// Example:
+//
+//	testastic.AssertVisible(t, resp.Body, "form#login button[type=submit]")
+func AssertVisible[T any](tb Reporter, actual T, selector string) {
+	tb.Helper()
+
+	assertVisibility(tb, "AssertVisible", actual, selector, false)
+}
+
+// AssertHidden asserts that every element matching selector in actual is hidden.
+// See AssertVisible for how visibility is determined and which selectors are supported.
+//
+// Example:
+//
+//	testastic.AssertHidden(t, resp.Body, "#error-banner")
+func AssertHidden[T any](tb Reporter, actual T, selector string) {
+	tb.Helper()
+
+	assertVisibility(tb, "AssertHidden", actual, selector, true)
+}
+
+// assertVisibility checks the visibility of all elements matching selector.
+func assertVisibility[T any](tb Reporter, name string, actual T, selector string, wantHidden bool) {
+	tb.Helper()
+
+	matches, ancestors, ok := selectActualHTML(tb, actual, selector)
+	if !ok {
+		return
+	}
+
+	want := "visible"
+	if wantHidden {
+		want = "hidden"
+	}
+
+	if len(matches) == 0 {
+		fail(tb, name, fmt.Sprintf("%s element matching %q", want, selector), "no matching element", nil)
+
+		return
+	}
+
+	for i, node := range matches {
+		reason := hiddenReason(node, ancestors[i])
+
+		switch {
+		case wantHidden && reason == "":
+			fail(tb, name, fmt.Sprintf("hidden element matching %q", selector), "visible at "+node.Path, nil)
+		case !wantHidden && reason != "":
+			fail(tb, name, fmt.Sprintf("visible element matching %q", selector), "hidden at "+node.Path+" ("+reason+")", nil)
+		}
+	}
+}
+
+// AssertHTMLAll asserts that predicate holds for every element matching selector in
+// actual, e.g. to audit that all form inputs have a name. Each failing element is
+// reported with its path. The assertion passes if no element matches. See
+// AssertVisible for the supported selectors.
+//
+// Example:
+//
+//	testastic.AssertHTMLAll(t, resp.Body, "form input", func(n *testastic.HTMLNode) bool {
+//		_, ok := n.Attributes["name"]
+//		return ok
+//	})
+func AssertHTMLAll[T any](tb Reporter, actual T, selector string, predicate func(*HTMLNode) bool) {
+	tb.Helper()
+
+	matches, _, ok := selectActualHTML(tb, actual, selector)
+	if !ok {
+		return
+	}
+
+	for _, node := range matches {
+		if !predicate(node) {
+			fail(tb, "AssertHTMLAll", fmt.Sprintf("every element matching %q satisfies the predicate", selector),
+				"not satisfied at "+node.Path, nil)
+		}
+	}
+}
+
+// selectActualHTML parses actual and returns the elements matching selector with their
+// ancestors. Parse errors are reported with Fatalf and return false.
+func selectActualHTML[T any](tb Reporter, actual T, selector string) ([]*HTMLNode, [][]*HTMLNode, bool) {
+	tb.Helper()
+
+	sel, err := parseSelector(selector)
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return nil, nil, false
+	}
+
+	actualBytes, err := toHTMLBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return nil, nil, false
+	}
+
+	root, err := parseActualHTMLBytes(actualBytes)
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return nil, nil, false
+	}
+
+	matches, ancestors := selectHTML(root, sel)
+
+	return matches, ancestors, true
+}
+
+// hiddenReason returns why node is hidden, or an empty string if it is visible.
+// The node itself is checked first, then its ancestors from the innermost.
+func hiddenReason(node *HTMLNode, ancestors []*HTMLNode) string {
+	reason := hiddenByAttributes(node)
+	if reason != "" {
+		return reason
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		reason = hiddenByAttributes(ancestors[i])
+		if reason != "" {
+			return reason + " on ancestor " + ancestors[i].Path
+		}
+	}
+
+	return ""
+}
+
+// hiddenByAttributes checks a single element for hidden, aria-hidden and display:none.
+func hiddenByAttributes(node *HTMLNode) string {
+	if _, ok := node.Attributes["hidden"]; ok {
+		return "hidden attribute"
+	}
+
+	if strings.EqualFold(strings.TrimSpace(getString(node.Attributes["aria-hidden"])), "true") {
+		return `aria-hidden="true"`
+	}
+
+	for decl := range strings.SplitSeq(getString(node.Attributes["style"]), ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(prop), "display") {
+			continue
+		}
+
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		if strings.EqualFold(value, "none") {
+			return "display:none"
+		}
+	}
+
+	return ""
 }
 
 // toHTMLBytes converts various input types to []byte.
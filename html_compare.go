@@ -2,8 +2,12 @@ package testastic
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // maxTextDisplayLen is the maximum length for displaying text values.
@@ -12,17 +16,120 @@ const maxTextDisplayLen = 30
 // nilDisplay is the string representation for nil values.
 const nilDisplay = "(nil)"
 
+// attrSpaceRegex matches whitespace runs in attribute values.
+var attrSpaceRegex = regexp.MustCompile(`\s+`)
+
+// attrPunctSpaceRegex matches a separator in an attribute value with the
+// surrounding space left by attrSpaceRegex.
+var attrPunctSpaceRegex = regexp.MustCompile(` ?([:;,]) ?`)
+
 // HTMLDifference represents a single difference between expected and actual HTML.
 type HTMLDifference struct {
 	Path     string
 	Expected any
 	Actual   any
 	Type     DiffType
+	Reason   string // Custom failure reason from a PathMatcher, if any
+}
+
+// CompareHTML compares actual HTML against expected HTML without a testing.TB, for
+// tooling such as auditing rendered templates. Expected may contain template matchers
+// like an expected file. The differences are returned sorted by path.
+//
+// Example:
+//
+//	diffs, err := testastic.CompareHTML(template, rendered, testastic.IgnoreHTMLComments())
+func CompareHTML(expected, actual []byte, opts ...HTMLOption) ([]HTMLDifference, error) {
+	cfg := newHTMLConfig(opts...)
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+
+	exp, err := ParseExpectedHTMLString(string(expected))
+	if err != nil {
+		return nil, err
+	}
+
+	_, diffs, err := compareHTMLDocuments(exp, actual, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffs, nil
+}
+
+// compareHTMLDocuments parses actual HTML and compares it against the parsed expected
+// HTML. It returns the actual tree for diff output and the differences sorted by path.
+func compareHTMLDocuments(expected *ExpectedHTML, actual []byte, cfg *HTMLConfig) (*HTMLNode, []HTMLDifference, error) {
+	actualNode, err := parseActualHTMLBytes(actual)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diffs := compareHTML(expected.Root, actualNode, cfg)
+	diffs = append(diffs, missingRequiredAttributes(actualNode, cfg)...)
+	sortHTMLDiffs(diffs)
+
+	return actualNode, diffs, nil
+}
+
+// missingRequiredAttributes reports the attributes required by RequireAttributes that
+// are missing on elements of the actual tree.
+func missingRequiredAttributes(node *HTMLNode, cfg *HTMLConfig) []HTMLDifference {
+	if node == nil || node.Type != HTMLElement || len(cfg.RequiredAttributes) == 0 {
+		return nil
+	}
+
+	if cfg.isElementIgnored(node.Tag) {
+		return nil
+	}
+
+	var diffs []HTMLDifference
+
+	for _, attr := range cfg.RequiredAttributes[strings.ToLower(node.Tag)] {
+		if hasAttribute(node, attr) {
+			continue
+		}
+
+		diffs = append(diffs, HTMLDifference{
+			Path:     node.Path + " @" + attr,
+			Expected: Present().String(),
+			Actual:   nil,
+			Type:     DiffRemoved,
+			Reason:   fmt.Sprintf("<%s> elements require the %s attribute", node.Tag, attr),
+		})
+	}
+
+	for _, child := range node.Children {
+		diffs = append(diffs, missingRequiredAttributes(child, cfg)...)
+	}
+
+	return diffs
+}
+
+// hasAttribute reports whether node has the attribute name, compared case-insensitively.
+func hasAttribute(node *HTMLNode, name string) bool {
+	if _, ok := node.Attributes[name]; ok {
+		return true
+	}
+
+	for attr := range node.Attributes {
+		if strings.EqualFold(attr, name) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // compareHTML compares expected and actual HTML nodes.
 // Returns a list of differences found.
 func compareHTML(expected, actual *HTMLNode, cfg *HTMLConfig) []HTMLDifference {
+	if cfg.IgnoreDoctype {
+		expected = unwrapDocument(expected, cfg)
+		actual = unwrapDocument(actual, cfg)
+	}
+
 	if expected == nil && actual == nil {
 		return nil
 	}
@@ -48,6 +155,109 @@ func compareHTML(expected, actual *HTMLNode, cfg *HTMLConfig) []HTMLDifference {
 	return compareHTMLNodes(expected, actual, expected.Path, cfg)
 }
 
+// unwrapDocument returns the root element of a #document wrapper if it is the only
+// significant child, and node itself otherwise.
+func unwrapDocument(node *HTMLNode, cfg *HTMLConfig) *HTMLNode {
+	if node == nil || node.Tag != "#document" {
+		return node
+	}
+
+	children := filterSignificantChildren(node.Children, node.Path, cfg)
+	if len(children) != 1 || children[0].Type != HTMLElement {
+		return node
+	}
+
+	return children[0]
+}
+
+// fragmentRoot returns the single root element of a fragment parsed as a document.
+// The parser places fragments inside html > head/body, so those wrappers are skipped.
+func fragmentRoot(doc *HTMLNode, cfg *HTMLConfig) (*HTMLNode, error) {
+	top := []*HTMLNode{doc}
+	if doc.Tag == "#document" {
+		top = elementChildren(doc, cfg)
+	}
+
+	var roots []*HTMLNode
+
+	for _, node := range top {
+		if node.Tag != "html" {
+			roots = append(roots, node)
+
+			continue
+		}
+
+		for _, section := range elementChildren(node, cfg) {
+			if section.Tag == "head" || section.Tag == "body" {
+				roots = append(roots, elementChildren(section, cfg)...)
+			} else {
+				roots = append(roots, section)
+			}
+		}
+	}
+
+	if len(roots) != 1 {
+		return nil, ErrInvalidHTMLFragment
+	}
+
+	return roots[0], nil
+}
+
+// elementChildren returns the significant element children of a node.
+func elementChildren(node *HTMLNode, cfg *HTMLConfig) []*HTMLNode {
+	var result []*HTMLNode
+
+	for _, child := range filterSignificantChildren(node.Children, node.Path, cfg) {
+		if child.Type == HTMLElement {
+			result = append(result, child)
+		}
+	}
+
+	return result
+}
+
+// findHTMLFragment searches the actual tree for an element matching the fragment.
+// It returns the first match with no differences, or otherwise the closest candidate
+// (preferring elements with the same tag) and its differences.
+func findHTMLFragment(fragment, actual *HTMLNode, cfg *HTMLConfig) (*HTMLNode, []HTMLDifference) {
+	var (
+		closest      *HTMLNode
+		closestDiffs []HTMLDifference
+		closestTag   bool
+	)
+
+	var walk func(node *HTMLNode) bool
+
+	walk = func(node *HTMLNode) bool {
+		if node.Type == HTMLElement && node.Tag != "#document" {
+			diffs := compareHTMLNodes(fragment, node, node.Path, cfg)
+			if len(diffs) == 0 {
+				closest, closestDiffs = node, nil
+
+				return true
+			}
+
+			sameTag := strings.EqualFold(node.Tag, fragment.Tag)
+			if closest == nil || (sameTag && !closestTag) ||
+				(sameTag == closestTag && len(diffs) < len(closestDiffs)) {
+				closest, closestDiffs, closestTag = node, diffs, sameTag
+			}
+		}
+
+		for _, child := range node.Children {
+			if walk(child) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	walk(actual)
+
+	return closest, closestDiffs
+}
+
 // compareHTMLNodes recursively compares two HTML nodes.
 //
 //nolint:funlen // Complex type dispatch is clearer in one function.
@@ -63,13 +273,16 @@ func compareHTMLNodes(expected, actual *HTMLNode, path string, cfg *HTMLConfig)
 				return nil
 			}
 
-			actualText := getTextContent(actual)
-			if !m.Match(actualText) {
+			actualText := cfg.normalizeText(getTextContent(actual))
+
+			matched, reason := matchAt(m, path, actualText)
+			if !matched {
 				return []HTMLDifference{{
 					Path:     path,
-					Expected: m.String(),
+					Expected: describeMismatch(m, actualText),
 					Actual:   actualText,
 					Type:     DiffMatcherFailed,
+					Reason:   reason,
 				}}
 			}
 
@@ -77,7 +290,7 @@ func compareHTMLNodes(expected, actual *HTMLNode, path string, cfg *HTMLConfig)
 		}
 
 		if ts, ok := expected.Text.(TemplateString); ok {
-			actualText := getTextContent(actual)
+			actualText := cfg.normalizeText(getTextContent(actual))
 			if !ts.Match(actualText) {
 				return []HTMLDifference{{
 					Path:     path,
@@ -120,20 +333,24 @@ func compareHTMLNodes(expected, actual *HTMLNode, path string, cfg *HTMLConfig)
 		// Compare attributes
 		diffs = append(diffs, compareHTMLAttributes(expected.Attributes, actual.Attributes, path, cfg)...)
 
+		if cfg.DetectDuplicateAttrs {
+			diffs = append(diffs, compareDuplicateAttributes(expected, actual, path, cfg)...)
+		}
+
 		// Compare children
 		diffs = append(diffs, compareHTMLChildren(expected.Children, actual.Children, path, cfg)...)
 
 	case HTMLText:
-		expText := getTextContent(expected)
-		actText := getTextContent(actual)
+		expText := cfg.normalizeText(getTextContent(expected))
+		actText := cfg.normalizeText(getTextContent(actual))
 
 		// Normalize whitespace unless preserving
-		if !cfg.PreserveWhitespace {
+		if !cfg.preservesWhitespace(path) {
 			expText = normalizeWhitespace(expText)
 			actText = normalizeWhitespace(actText)
 		}
 
-		if expText != actText {
+		if expText != actText && !cfg.isTextIgnored(expText, actText) {
 			diffs = append(diffs, HTMLDifference{
 				Path:     path,
 				Expected: expText,
@@ -158,7 +375,7 @@ func compareHTMLNodes(expected, actual *HTMLNode, path string, cfg *HTMLConfig)
 		}
 
 	case HTMLDoctype:
-		if !strings.EqualFold(expected.Tag, actual.Tag) {
+		if !cfg.IgnoreDoctype && !strings.EqualFold(expected.Tag, actual.Tag) {
 			diffs = append(diffs, HTMLDifference{
 				Path:     path,
 				Expected: expected.Tag,
@@ -191,6 +408,10 @@ func compareHTMLAttributes(expected, actual map[string]any, path string, cfg *HT
 		attrPath := path + " @" + name
 		actVal, exists := actual[name]
 
+		if exists && cfg.normalizesAttributeWhitespace(name) {
+			expVal, actVal = normalizeAttrValue(expVal), normalizeAttrValue(actVal)
+		}
+
 		if !exists {
 			diffs = append(diffs, HTMLDifference{
 				Path:     attrPath,
@@ -204,21 +425,32 @@ func compareHTMLAttributes(expected, actual map[string]any, path string, cfg *HT
 
 		if m, ok := expVal.(Matcher); ok {
 			actStr := getString(actVal)
-			if !m.Match(actStr) {
+
+			matched, reason := matchAt(m, attrPath, actStr)
+			if !matched {
 				diffs = append(diffs, HTMLDifference{
 					Path:     attrPath,
-					Expected: m.String(),
+					Expected: describeMismatch(m, actStr),
 					Actual:   actStr,
 					Type:     DiffMatcherFailed,
+					Reason:   reason,
 				})
 			}
 
 			continue
 		}
 
+		classSet := strings.EqualFold(name, "class") && !cfg.StrictClassOrder
+
 		if ts, ok := expVal.(TemplateString); ok {
 			actStr := getString(actVal)
-			if !ts.Match(actStr) {
+
+			matched := ts.Match(actStr)
+			if classSet {
+				matched = matchClassTemplate(ts, actStr)
+			}
+
+			if !matched {
 				diffs = append(diffs, HTMLDifference{
 					Path:     attrPath,
 					Expected: ts.String(),
@@ -233,6 +465,10 @@ func compareHTMLAttributes(expected, actual map[string]any, path string, cfg *HT
 		expStr := getString(expVal)
 		actStr := getString(actVal)
 
+		if classSet && slices.Equal(classTokens(expStr), classTokens(actStr)) {
+			continue
+		}
+
 		if expStr != actStr {
 			diffs = append(diffs, HTMLDifference{
 				Path:     attrPath,
@@ -265,13 +501,18 @@ func compareHTMLAttributes(expected, actual map[string]any, path string, cfg *HT
 // compareHTMLChildren compares child nodes of an HTML element.
 func compareHTMLChildren(expected, actual []*HTMLNode, path string, cfg *HTMLConfig) []HTMLDifference {
 	// Filter out nodes that should be ignored
-	expFiltered := filterSignificantChildren(expected, cfg)
-	actFiltered := filterSignificantChildren(actual, cfg)
+	expFiltered := filterSignificantChildren(expected, path, cfg)
+	actFiltered := filterSignificantChildren(actual, path, cfg)
 
 	if cfg.shouldIgnoreChildOrder(path) {
 		return compareChildrenUnordered(expFiltered, actFiltered, path, cfg)
 	}
 
+	if attr, ok := cfg.SortChildrenByAttr[path]; ok {
+		expFiltered = sortNodesByAttr(expFiltered, attr)
+		actFiltered = sortNodesByAttr(actFiltered, attr)
+	}
+
 	return compareChildrenOrdered(expFiltered, actFiltered, path, cfg)
 }
 
@@ -334,7 +575,7 @@ func compareChildrenUnordered(expected, actual []*HTMLNode, path string, cfg *HT
 				continue
 			}
 
-			if len(compareHTMLNodes(exp, act, path, cfg)) == 0 {
+			if len(compareHTMLNodes(exp, act, buildChildPath(path, exp, i), cfg)) == 0 {
 				used[j] = true
 				found = true
 
@@ -380,8 +621,179 @@ func compareChildrenUnordered(expected, actual []*HTMLNode, path string, cfg *HT
 	return nil
 }
 
-// filterSignificantChildren filters out insignificant nodes.
-func filterSignificantChildren(nodes []*HTMLNode, cfg *HTMLConfig) []*HTMLNode {
+// compareDuplicateAttributes reports attributes duplicated in actual, unless expected
+// contains the same duplicates.
+func compareDuplicateAttributes(expected, actual *HTMLNode, path string, cfg *HTMLConfig) []HTMLDifference {
+	names := make([]string, 0, len(actual.DuplicateAttributes))
+	for name := range actual.DuplicateAttributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var diffs []HTMLDifference
+
+	for _, name := range names {
+		actVals := actual.DuplicateAttributes[name]
+		if cfg.isAttributeIgnored(path, name) || slices.Equal(expected.DuplicateAttributes[name], actVals) {
+			continue
+		}
+
+		quoted := make([]string, len(actVals))
+		for i, v := range actVals {
+			quoted[i] = fmt.Sprintf("%q", v)
+		}
+
+		diffs = append(diffs, HTMLDifference{
+			Path:     path + " @" + name,
+			Expected: formatAttrValue(expected.Attributes[name]),
+			Actual:   strings.Join(quoted, ", "),
+			Type:     DiffChanged,
+			Reason:   fmt.Sprintf("attribute %s appears %d times", name, len(actVals)),
+		})
+	}
+
+	return diffs
+}
+
+// classTokens splits a class attribute into sorted, deduplicated class names.
+func classTokens(s string) []string {
+	tokens := strings.Fields(s)
+	slices.Sort(tokens)
+
+	return slices.Compact(tokens)
+}
+
+// matchClassTemplate matches a class attribute with embedded matchers as a set.
+// The template is tokenized on whitespace after matcher resolution; literal class
+// names must be present, and each token with a matcher must match a distinct
+// remaining class name.
+func matchClassTemplate(ts TemplateString, actual string) bool {
+	actTokens := classTokens(actual)
+	used := make([]bool, len(actTokens))
+
+	var literals, templates []TemplateString
+
+	for _, tok := range tokenizeTemplate(ts) {
+		if len(tok.Segments) == 1 && tok.Segments[0].Matcher == nil {
+			literals = append(literals, tok)
+		} else {
+			templates = append(templates, tok)
+		}
+	}
+
+	slices.SortFunc(literals, func(a, b TemplateString) int { return strings.Compare(a.Original, b.Original) })
+	literals = slices.CompactFunc(literals, func(a, b TemplateString) bool { return a.Original == b.Original })
+
+	if len(literals)+len(templates) != len(actTokens) {
+		return false
+	}
+
+	for _, tok := range literals {
+		idx := slices.Index(actTokens, tok.Original)
+		if idx < 0 {
+			return false
+		}
+
+		used[idx] = true
+	}
+
+	for _, tok := range templates {
+		found := false
+
+		for i, act := range actTokens {
+			if !used[i] && tok.Match(act) {
+				used[i] = true
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tokenizeTemplate splits a template string on whitespace in its literal segments.
+// Matcher segments stay attached to the adjacent literal text of the same token.
+func tokenizeTemplate(ts TemplateString) []TemplateString {
+	var (
+		tokens  []TemplateString
+		current TemplateString
+	)
+
+	flush := func() {
+		if len(current.Segments) > 0 {
+			tokens = append(tokens, current)
+		}
+
+		current = TemplateString{}
+	}
+
+	for _, seg := range ts.Segments {
+		if seg.Matcher != nil {
+			current.Segments = append(current.Segments, seg)
+			current.Original += seg.Matcher.String()
+
+			continue
+		}
+
+		for i, r := range seg.Literal {
+			if unicode.IsSpace(r) {
+				flush()
+
+				continue
+			}
+
+			char := seg.Literal[i : i+utf8.RuneLen(r)]
+			last := len(current.Segments) - 1
+
+			if last >= 0 && current.Segments[last].Matcher == nil {
+				current.Segments[last].Literal += char
+			} else {
+				current.Segments = append(current.Segments, TemplateSegment{Literal: char})
+			}
+
+			current.Original += char
+		}
+	}
+
+	flush()
+
+	return tokens
+}
+
+// sortNodesByAttr returns the nodes stably sorted by the string value of the given attribute.
+// Nodes without a literal value for the attribute are sorted to the end.
+func sortNodesByAttr(nodes []*HTMLNode, attr string) []*HTMLNode {
+	sorted := slices.Clone(nodes)
+
+	slices.SortStableFunc(sorted, func(a, b *HTMLNode) int {
+		aVal, aOK := a.Attributes[attr].(string)
+		bVal, bOK := b.Attributes[attr].(string)
+
+		switch {
+		case aOK && bOK:
+			return strings.Compare(aVal, bVal)
+		case aOK:
+			return -1
+		case bOK:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return sorted
+}
+
+// filterSignificantChildren filters out insignificant nodes among the children of the
+// element at parentPath.
+func filterSignificantChildren(nodes []*HTMLNode, parentPath string, cfg *HTMLConfig) []*HTMLNode {
 	result := make([]*HTMLNode, 0, len(nodes))
 
 	for _, node := range nodes {
@@ -399,8 +811,12 @@ func filterSignificantChildren(nodes []*HTMLNode, cfg *HTMLConfig) []*HTMLNode {
 			continue
 		}
 
+		if node.Type == HTMLDoctype && cfg.IgnoreDoctype {
+			continue
+		}
+
 		// Skip whitespace-only text nodes unless preserving whitespace
-		if node.Type == HTMLText && !cfg.PreserveWhitespace {
+		if node.Type == HTMLText && !cfg.preservesWhitespace(parentPath) {
 			text := getTextContent(node)
 			if strings.TrimSpace(text) == "" {
 				continue
@@ -526,6 +942,49 @@ func formatAttrValue(v any) string {
 	return fmt.Sprintf("%q", getString(v))
 }
 
+// normalizeAttrValue normalizes whitespace in an attribute value for
+// NormalizeAttributeWhitespace. In a TemplateString, only literal segments are
+// normalized, keeping the space between a literal and an adjacent matcher.
+func normalizeAttrValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return collapseAttrSpace(strings.TrimSpace(val))
+
+	case TemplateString:
+		segments := make([]TemplateSegment, len(val.Segments))
+
+		for i, seg := range val.Segments {
+			if seg.Matcher == nil {
+				lit := seg.Literal
+				if i == 0 {
+					lit = strings.TrimLeftFunc(lit, unicode.IsSpace)
+				}
+
+				if i == len(val.Segments)-1 {
+					lit = strings.TrimRightFunc(lit, unicode.IsSpace)
+				}
+
+				seg.Literal = collapseAttrSpace(lit)
+			}
+
+			segments[i] = seg
+		}
+
+		return TemplateString{Segments: segments, Original: val.Original}
+
+	default:
+		return v
+	}
+}
+
+// collapseAttrSpace collapses whitespace runs to one space and drops whitespace
+// around ':', ';' and ','.
+func collapseAttrSpace(s string) string {
+	s = attrSpaceRegex.ReplaceAllString(s, " ")
+
+	return attrPunctSpaceRegex.ReplaceAllString(s, "$1")
+}
+
 // normalizeWhitespace collapses whitespace in text.
 func normalizeWhitespace(s string) string {
 	// Collapse multiple whitespace to single space
@@ -2,6 +2,7 @@ package testastic
 
 import (
 	"fmt"
+	"html"
 	"sort"
 	"strings"
 )
@@ -28,6 +29,13 @@ func FormatHTMLDiff(diffs []HTMLDifference) string {
 		sb.WriteString(fmt.Sprintf("HTML mismatch at %d paths:\n", len(diffs)))
 	}
 
+	counts := make(map[DiffType]int)
+	for _, d := range diffs {
+		counts[d.Type]++
+	}
+
+	sb.WriteString(formatDiffSummary(counts))
+
 	for _, d := range diffs {
 		sb.WriteString("\n")
 		sb.WriteString(fmt.Sprintf("  %s\n", d.Path))
@@ -49,6 +57,24 @@ func FormatHTMLDiff(diffs []HTMLDifference) string {
 			sb.WriteString(fmt.Sprintf("    expected: %s\n", formatHTMLValue(d.Expected)))
 			sb.WriteString(fmt.Sprintf("    actual:   %s\n", formatHTMLValue(d.Actual)))
 		}
+
+		if d.Reason != "" {
+			sb.WriteString(fmt.Sprintf("    reason:   %s\n", d.Reason))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatHTMLReasons lists the custom failure reasons of the differences, if any.
+// It complements FormatHTMLDiffInline, which shows values but not reasons.
+func formatHTMLReasons(diffs []HTMLDifference) string {
+	var sb strings.Builder
+
+	for _, d := range diffs {
+		if d.Reason != "" {
+			sb.WriteString(fmt.Sprintf("\n  %s\n    reason: %s\n", d.Path, d.Reason))
+		}
 	}
 
 	return sb.String()
@@ -74,6 +100,40 @@ func FormatHTMLDiffInline(expected, actual *HTMLNode) string {
 	return sb.String()
 }
 
+// FormatHTMLDiffHTML generates a browsable HTML document showing an inline diff
+// between expected and actual HTML. Removed lines are wrapped in <del> and added
+// lines in <ins>, e.g. for attaching to a PR review as an artifact.
+func FormatHTMLDiffHTML(expected, actual *HTMLNode) string {
+	expLines := strings.Split(renderPrettyHTML(expected, 0), "\n")
+	actLines := strings.Split(renderPrettyHTML(actual, 0), "\n")
+
+	var sb strings.Builder
+
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	sb.WriteString("<meta charset=\"utf-8\">\n<title>testastic HTML diff</title>\n")
+	sb.WriteString("<style>\n")
+	sb.WriteString("del { background: #ffebe9; color: #82071e; text-decoration: none; }\n")
+	sb.WriteString("ins { background: #dafbe1; color: #116329; text-decoration: none; }\n")
+	sb.WriteString("</style>\n</head>\n<body>\n<pre>\n")
+
+	for _, op := range diffLines(expLines, actLines) {
+		line := html.EscapeString(op.line)
+
+		switch op.op {
+		case diffEqual:
+			sb.WriteString("  " + line + "\n")
+		case diffDelete:
+			sb.WriteString("<del>- " + line + "</del>\n")
+		case diffInsert:
+			sb.WriteString("<ins>+ " + line + "</ins>\n")
+		}
+	}
+
+	sb.WriteString("</pre>\n</body>\n</html>\n")
+
+	return sb.String()
+}
+
 // renderPrettyHTML renders an HTMLNode tree as formatted HTML string.
 //
 //nolint:gocognit,funlen // HTML rendering requires handling multiple cases and statements.
@@ -1,20 +1,45 @@
 package testastic
 
 import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
 )
 
+// ErrInvalidAttributePattern is returned when an IgnoreAttributesMatching pattern is malformed.
+var ErrInvalidAttributePattern = errors.New("invalid attribute pattern")
+
+// ErrInvalidTextPattern is returned when an IgnoreTextMatching pattern is not a valid regex.
+var ErrInvalidTextPattern = errors.New("invalid text pattern")
+
 // HTMLConfig holds the configuration for HTML comparison.
 type HTMLConfig struct {
+	DetectDuplicateAttrs  bool
 	IgnoreComments        bool
+	IgnoreDoctype         bool
 	PreserveWhitespace    bool
+	PreserveWhitespaceIn  []string
+	Record                bool
+	TreatNbspAsSpace      bool
 	IgnoreChildOrder      bool
 	IgnoreChildOrderPaths []string
+	SortChildrenByAttr    map[string]string
 	IgnoredElements       []string
 	IgnoredAttributes     []string
 	IgnoredAttributePaths []string
+	IgnoredAttrPatterns   []string
+	IgnoredTextPatterns   []*regexp.Regexp
+	NormalizeSpaceAttrs   []string
+	NormalizeSpaceAll     bool
+	RequiredAttributes    map[string][]string
+	StrictClassOrder      bool
 	Update                bool
+
+	err error // First invalid option, reported when the assertion runs
 }
 
 // HTMLOption is a functional option for configuring HTML comparison.
@@ -27,6 +52,17 @@ func IgnoreHTMLComments() HTMLOption {
 	}
 }
 
+// IgnoreDoctype excludes <!DOCTYPE> declarations from comparison, so a golden file
+// with a doctype matches output without one and vice versa. A document with a doctype
+// is parsed into a #document wrapper around the doctype and the root element; with
+// this option the wrapper is unwrapped to its root element, so paths start at html
+// either way.
+func IgnoreDoctype() HTMLOption {
+	return func(c *HTMLConfig) {
+		c.IgnoreDoctype = true
+	}
+}
+
 // PreserveWhitespace disables whitespace normalization.
 // By default, insignificant whitespace is collapsed.
 func PreserveWhitespace() HTMLOption {
@@ -35,6 +71,51 @@ func PreserveWhitespace() HTMLOption {
 	}
 }
 
+// PreserveWhitespaceIn disables whitespace normalization for text inside the given
+// elements, e.g. pre and code, including text in elements nested in them, while it
+// stays enabled everywhere else. This mirrors how browsers render pre.
+//
+// Example:
+//
+//	testastic.AssertHTML(t, "testdata/docs.expected.html", resp.Body, testastic.PreserveWhitespaceIn("pre", "code"))
+func PreserveWhitespaceIn(tags ...string) HTMLOption {
+	return func(c *HTMLConfig) {
+		c.PreserveWhitespaceIn = append(c.PreserveWhitespaceIn, tags...)
+	}
+}
+
+// preservesWhitespace reports whether whitespace of text at path is compared exactly,
+// either globally or because the text is inside an element given to PreserveWhitespaceIn.
+func (c *HTMLConfig) preservesWhitespace(path string) bool {
+	if c.PreserveWhitespace {
+		return true
+	}
+
+	if len(c.PreserveWhitespaceIn) == 0 {
+		return false
+	}
+
+	for segment := range strings.SplitSeq(strings.TrimSuffix(path, " (text)"), " > ") {
+		tag, _, _ := strings.Cut(segment, "[")
+
+		for _, preserved := range c.PreserveWhitespaceIn {
+			if strings.EqualFold(tag, preserved) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TreatNbspAsSpace replaces non-breaking spaces (U+00A0, e.g. from &nbsp;) with
+// regular spaces in text content before comparison.
+func TreatNbspAsSpace() HTMLOption {
+	return func(c *HTMLConfig) {
+		c.TreatNbspAsSpace = true
+	}
+}
+
 // IgnoreChildOrder makes child element comparison order-insensitive globally.
 func IgnoreChildOrder() HTMLOption {
 	return func(c *HTMLConfig) {
@@ -49,6 +130,27 @@ func IgnoreChildOrderAt(path string) HTMLOption {
 	}
 }
 
+// IgnoreHeadOrder makes the order of <head> children such as <meta>, <link> and
+// <script> irrelevant, since bundlers often emit them in nondeterministic order.
+// It is shorthand for IgnoreChildOrderAt("html > head").
+func IgnoreHeadOrder() HTMLOption {
+	return IgnoreChildOrderAt("html > head")
+}
+
+// SortChildrenBy sorts the child elements at the specified HTML path by the value of
+// the given attribute before an ordered comparison. Children without the attribute
+// are kept after the sorted ones in their original order. This is faster than
+// IgnoreChildOrderAt and keeps index-based diffs meaningful.
+func SortChildrenBy(path, attr string) HTMLOption {
+	return func(c *HTMLConfig) {
+		if c.SortChildrenByAttr == nil {
+			c.SortChildrenByAttr = make(map[string]string)
+		}
+
+		c.SortChildrenByAttr[path] = attr
+	}
+}
+
 // IgnoreElements excludes elements matching the specified tag names from comparison.
 func IgnoreElements(tags ...string) HTMLOption {
 	return func(c *HTMLConfig) {
@@ -63,14 +165,113 @@ func IgnoreAttributes(attrs ...string) HTMLOption {
 	}
 }
 
+// IgnoreTextMatching skips the comparison of text nodes whose expected or actual text
+// matches the regex pattern, e.g. `\d+ (minute|hour)s ago` for relative timestamps.
+// The pattern is matched anywhere in the text after whitespace normalization; anchor
+// it with ^ and $ to require a full match. Text containing matchers is checked by its
+// matchers instead, so the pattern only applies to literal text. An invalid pattern
+// fails the assertion.
+func IgnoreTextMatching(pattern string) HTMLOption {
+	return func(c *HTMLConfig) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			if c.err == nil {
+				c.err = fmt.Errorf("%w: %q: %w", ErrInvalidTextPattern, pattern, err)
+			}
+
+			return
+		}
+
+		c.IgnoredTextPatterns = append(c.IgnoredTextPatterns, re)
+	}
+}
+
+// RequireAttributes requires every element with the given tag in the actual HTML to
+// have the given attributes, with any value, e.g. for accessibility audits. It applies
+// to all matching elements, including ones the expected file does not describe. Each
+// missing attribute is reported as removed. Use {{present}} as an attribute value in
+// the expected file to require an attribute on a single element instead.
+// Missing required attributes still fail the assertion in update mode, since updating
+// the expected file cannot fix them.
+//
+// Example:
+//
+//	testastic.AssertHTML(t, "testdata/gallery.expected.html", resp.Body, testastic.RequireAttributes("img", "alt"))
+func RequireAttributes(tag string, attrs ...string) HTMLOption {
+	return func(c *HTMLConfig) {
+		if c.RequiredAttributes == nil {
+			c.RequiredAttributes = make(map[string][]string)
+		}
+
+		tag = strings.ToLower(tag)
+		c.RequiredAttributes[tag] = append(c.RequiredAttributes[tag], attrs...)
+	}
+}
+
+// IgnoreAttributesMatching excludes attributes whose names match any of the glob
+// patterns from comparison globally, e.g. "data-*" or "aria-*". Patterns use
+// path.Match syntax, are anchored to the whole attribute name, and are matched
+// case-insensitively. An invalid pattern fails the assertion.
+func IgnoreAttributesMatching(patterns ...string) HTMLOption {
+	return func(c *HTMLConfig) {
+		for _, p := range patterns {
+			p = strings.ToLower(p)
+
+			_, err := path.Match(p, "")
+			if err != nil {
+				if c.err == nil {
+					c.err = fmt.Errorf("%w: %q", ErrInvalidAttributePattern, p)
+				}
+
+				continue
+			}
+
+			c.IgnoredAttrPatterns = append(c.IgnoredAttrPatterns, p)
+		}
+	}
+}
+
 // IgnoreAttributeAt excludes a specific attribute at a given path.
-// Format: "path@attribute" e.g., "html > body > div@class".
+// Format: "path@attribute" e.g., "html > body > div@class". The attribute name
+// is matched case-insensitively.
 func IgnoreAttributeAt(pathAttr string) HTMLOption {
 	return func(c *HTMLConfig) {
 		c.IgnoredAttributePaths = append(c.IgnoredAttributePaths, pathAttr)
 	}
 }
 
+// NormalizeAttributeWhitespace ignores formatting differences in the values of the
+// named attributes, e.g. style, srcset or class, or of all attributes if none are
+// named. Runs of whitespace are collapsed to one space, leading and trailing
+// whitespace is removed, and whitespace around ':', ';' and ',' is dropped, so
+// style="color: red" matches style="color:red". Embedded matchers still apply.
+func NormalizeAttributeWhitespace(attrs ...string) HTMLOption {
+	return func(c *HTMLConfig) {
+		if len(attrs) == 0 {
+			c.NormalizeSpaceAll = true
+		}
+
+		c.NormalizeSpaceAttrs = append(c.NormalizeSpaceAttrs, attrs...)
+	}
+}
+
+// StrictClassOrder compares class attributes as exact strings.
+// By default, class attributes are compared as unordered sets of class names.
+func StrictClassOrder() HTMLOption {
+	return func(c *HTMLConfig) {
+		c.StrictClassOrder = true
+	}
+}
+
+// DetectDuplicateAttributes reports attributes that appear more than once on an
+// element in actual, e.g. class="a" class="b". Either way, only the first value is
+// compared, as browsers use it; without this option the others are silently dropped.
+func DetectDuplicateAttributes() HTMLOption {
+	return func(c *HTMLConfig) {
+		c.DetectDuplicateAttrs = true
+	}
+}
+
 // HTMLUpdate forces updating the expected file with the actual value.
 func HTMLUpdate() HTMLOption {
 	return func(c *HTMLConfig) {
@@ -78,12 +279,30 @@ func HTMLUpdate() HTMLOption {
 	}
 }
 
-// newHTMLConfig creates a new HTMLConfig with default values and applies options.
+// HTMLRecord writes the actual HTML to the expected file without comparing, like
+// Record for JSON. Also enabled by TESTASTIC_RECORD=1.
+func HTMLRecord() HTMLOption {
+	return func(c *HTMLConfig) {
+		c.Record = true
+	}
+}
+
+// newHTMLConfig creates a new HTMLConfig with default values and applies the options
+// set by SetDefaultHTMLOptions followed by opts.
 func newHTMLConfig(opts ...HTMLOption) *HTMLConfig {
 	cfg := &HTMLConfig{
+		Record: shouldRecord(),
 		Update: shouldUpdate(),
 	}
 
+	defaultHTMLOptions.RLock()
+	defaults := defaultHTMLOptions.opts
+	defaultHTMLOptions.RUnlock()
+
+	for _, opt := range defaults {
+		opt(cfg)
+	}
+
 	for _, opt := range opts {
 		opt(cfg)
 	}
@@ -91,6 +310,31 @@ func newHTMLConfig(opts ...HTMLOption) *HTMLConfig {
 	return cfg
 }
 
+// defaultHTMLOptions holds the options set by SetDefaultHTMLOptions.
+//
+//nolint:gochecknoglobals // Package-level defaults are the intended way to share policy.
+var defaultHTMLOptions struct {
+	sync.RWMutex
+
+	opts []HTMLOption
+}
+
+// SetDefaultHTMLOptions is like SetDefaultOptions for HTML assertions and comparisons.
+// The defaults are process-global in the same way.
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		testastic.SetDefaultHTMLOptions(testastic.IgnoreHTMLComments(), testastic.IgnoreDoctype())
+//		os.Exit(m.Run())
+//	}
+func SetDefaultHTMLOptions(opts ...HTMLOption) {
+	defaultHTMLOptions.Lock()
+	defer defaultHTMLOptions.Unlock()
+
+	defaultHTMLOptions.opts = slices.Clone(opts)
+}
+
 // shouldIgnoreChildOrder checks if child order should be ignored at the given path.
 func (c *HTMLConfig) shouldIgnoreChildOrder(path string) bool {
 	if c.IgnoreChildOrder {
@@ -106,6 +350,15 @@ func (c *HTMLConfig) shouldIgnoreChildOrder(path string) bool {
 	return false
 }
 
+// normalizeText applies text normalizations configured for comparison.
+func (c *HTMLConfig) normalizeText(s string) string {
+	if c.TreatNbspAsSpace {
+		s = strings.ReplaceAll(s, "\u00a0", " ")
+	}
+
+	return s
+}
+
 // isElementIgnored checks if an element with the given tag should be ignored.
 func (c *HTMLConfig) isElementIgnored(tag string) bool {
 	for _, t := range c.IgnoredElements {
@@ -126,8 +379,58 @@ func (c *HTMLConfig) isAttributeIgnored(path, attr string) bool {
 		}
 	}
 
-	// Check path-specific attribute ignores
-	pathAttr := path + "@" + attr
+	// Check global attribute patterns
+	if c.matchesAttributePattern(attr) {
+		return true
+	}
+
+	// Check path-specific attribute ignores; attribute names are case-insensitive
+	for _, pa := range c.IgnoredAttributePaths {
+		p, a, ok := strings.Cut(pa, "@")
+		if ok && p == path && strings.EqualFold(a, attr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizesAttributeWhitespace checks if whitespace in the value of attr is normalized.
+func (c *HTMLConfig) normalizesAttributeWhitespace(attr string) bool {
+	if c.NormalizeSpaceAll {
+		return true
+	}
+
+	for _, a := range c.NormalizeSpaceAttrs {
+		if strings.EqualFold(a, attr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTextIgnored checks if either text matches an IgnoreTextMatching pattern.
+func (c *HTMLConfig) isTextIgnored(expected, actual string) bool {
+	for _, re := range c.IgnoredTextPatterns {
+		if re.MatchString(expected) || re.MatchString(actual) {
+			return true
+		}
+	}
 
-	return slices.Contains(c.IgnoredAttributePaths, pathAttr)
+	return false
+}
+
+// matchesAttributePattern checks if an attribute name matches any IgnoreAttributesMatching pattern.
+func (c *HTMLConfig) matchesAttributePattern(attr string) bool {
+	name := strings.ToLower(attr)
+
+	for _, p := range c.IgnoredAttrPatterns {
+		matched, _ := path.Match(p, name)
+		if matched {
+			return true
+		}
+	}
+
+	return false
 }
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"golang.org/x/net/html"
 )
@@ -26,12 +28,13 @@ const (
 
 // HTMLNode represents a normalized HTML node for comparison.
 type HTMLNode struct {
-	Type       HTMLNodeType
-	Tag        string
-	Attributes map[string]any
-	Children   []*HTMLNode
-	Text       any
-	Path       string
+	Type                HTMLNodeType
+	Tag                 string
+	Attributes          map[string]any
+	DuplicateAttributes map[string][]string // Raw values of attributes that appear more than once
+	Children            []*HTMLNode
+	Text                any
+	Path                string
 }
 
 // ExpectedHTML represents a parsed expected HTML file with matchers.
@@ -53,28 +56,95 @@ type TemplateString struct {
 	Original string // For display: "border-left: 6px solid {{anyString}}".
 }
 
-// Match checks if the actual string matches the template pattern.
+// Match checks if the actual string matches the template pattern. Literal segments
+// must match exactly; each matcher segment must match the part of actual between them.
 func (t TemplateString) Match(actual string) bool {
-	var pattern strings.Builder
+	return matchSegments(t.Segments, actual)
+}
+
+// matchSegments reports whether s can be split so that each segment matches its part.
+// Matcher segments try the longest part first, like .* in a regex.
+func matchSegments(segments []TemplateSegment, s string) bool {
+	if len(segments) == 0 {
+		return s == ""
+	}
+
+	seg := segments[0]
+	if seg.Matcher == nil {
+		rest, ok := strings.CutPrefix(s, seg.Literal)
+
+		return ok && matchSegments(segments[1:], rest)
+	}
+
+	for end := len(s); end >= 0; end-- {
+		if end < len(s) && !utf8.RuneStart(s[end]) {
+			continue
+		}
 
-	pattern.WriteString("^")
+		// Only split where the following literal can match.
+		if len(segments) > 1 && segments[1].Matcher == nil && !strings.HasPrefix(s[end:], segments[1].Literal) {
+			continue
+		}
 
-	for _, seg := range t.Segments {
-		if seg.Matcher != nil {
-			pattern.WriteString(matcherToRegex(seg.Matcher))
-		} else {
-			pattern.WriteString(regexp.QuoteMeta(seg.Literal))
+		if matchSegment(seg.Matcher, s[:end]) && matchSegments(segments[1:], s[end:]) {
+			return true
 		}
 	}
 
-	pattern.WriteString("$")
+	return false
+}
 
-	re, err := regexp.Compile(pattern.String())
+// matchSegment reports whether a matcher embedded in a template matches part of the
+// text. Matchers for JSON types such as {{anyInt}} are checked against their textual
+// form with a regex; other matchers check the text with Match. not and allOf apply
+// the same rules to the matchers they wrap.
+func matchSegment(m Matcher, s string) bool {
+	switch v := m.(type) {
+	case *notMatcher:
+		return !matchSegment(v.matcher, s)
+	case *allOfMatcher:
+		for _, inner := range v.matchers {
+			if !matchSegment(inner, s) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	pattern, ok := matcherToRegex(m)
+	if !ok {
+		return m.Match(s)
+	}
+
+	re, err := compileTemplateRegex(pattern)
 	if err != nil {
 		return false
 	}
 
-	return re.MatchString(actual)
+	return re.MatchString(s)
+}
+
+// templateRegexCache holds compiled, anchored matcher regexes by pattern, since
+// matchSegments checks the same matcher against many candidate parts.
+//
+//nolint:gochecknoglobals // Cache shared by all template matches.
+var templateRegexCache sync.Map
+
+// compileTemplateRegex compiles pattern anchored to the whole string, using the cache.
+func compileTemplateRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := templateRegexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil //nolint:forcetypeassert // Only *regexp.Regexp is stored.
+	}
+
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid template regex %q: %w", pattern, err)
+	}
+
+	templateRegexCache.Store(pattern, re)
+
+	return re, nil
 }
 
 // String returns the original template representation.
@@ -82,27 +152,36 @@ func (t TemplateString) String() string {
 	return t.Original
 }
 
-// matcherToRegex converts a matcher to its regex equivalent.
-func matcherToRegex(m Matcher) string {
+// matcherToRegex converts a matcher to its regex equivalent. It returns false for
+// matchers without one, which are checked with Match instead.
+func matcherToRegex(m Matcher) (string, bool) {
 	switch v := m.(type) {
-	case anyStringMatcher:
-		return ".*"
+	case anyStringMatcher, anyValueMatcher, ignoreMatcher:
+		return "(?s:.*)", true
 	case anyIntMatcher:
-		return "-?\\d+"
+		return "-?\\d+", true
 	case anyFloatMatcher:
-		return "-?\\d+\\.?\\d*"
+		return "-?\\d+\\.?\\d*", true
 	case anyBoolMatcher:
-		return "(true|false)"
-	case anyValueMatcher:
-		return ".*"
-	case ignoreMatcher:
-		return ".*"
+		return "(true|false)", true
 	case *regexMatcher:
-		return v.pattern
+		if v.caseInsensitive {
+			return "(?i:" + v.pattern + ")", true
+		}
+
+		return "(?:" + v.pattern + ")", true
 	case *oneOfMatcher:
-		return oneOfToRegex(v.values)
+		return oneOfToRegex(v.values), true
+	case substringMatcher:
+		return "(?s:.*)" + regexp.QuoteMeta(v.substr) + "(?s:.*)", true
+	case affixMatcher:
+		if v.suffix {
+			return "(?s:.*)" + regexp.QuoteMeta(v.affix), true
+		}
+
+		return regexp.QuoteMeta(v.affix) + "(?s:.*)", true
 	default:
-		return ".*"
+		return "", false
 	}
 }
 
@@ -173,6 +252,12 @@ func ParseExpectedHTMLString(content string) (*ExpectedHTML, error) {
 	return expected, nil
 }
 
+// ParseHTML parses an HTML document into an HTMLNode tree without template
+// expressions, e.g. to query a response with Find for a custom assertion.
+func ParseHTML(data []byte) (*HTMLNode, error) {
+	return parseActualHTMLBytes(data)
+}
+
 // parseActualHTMLBytes parses actual HTML bytes into an HTMLNode tree.
 func parseActualHTMLBytes(data []byte) (*HTMLNode, error) {
 	doc, err := html.Parse(strings.NewReader(string(data)))
@@ -183,7 +268,35 @@ func parseActualHTMLBytes(data []byte) (*HTMLNode, error) {
 	return convertToHTMLNode(doc, nil, ""), nil
 }
 
+// duplicateAttributes returns the raw values of attributes that occur more than once.
+// The attribute map keeps only one value per name, so duplicates are recorded separately.
+func duplicateAttributes(attrs []html.Attribute) map[string][]string {
+	values := make(map[string][]string, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Key] = append(values[attr.Key], attr.Val)
+	}
+
+	var dups map[string][]string
+
+	for name, vals := range values {
+		if len(vals) < 2 { //nolint:mnd // More than one occurrence is a duplicate.
+			continue
+		}
+
+		if dups == nil {
+			dups = make(map[string][]string)
+		}
+
+		dups[name] = vals
+	}
+
+	return dups
+}
+
 // convertToHTMLNode converts an html.Node to an HTMLNode tree.
+// Attribute names are kept as parsed: the HTML5 parser lowercases them on both
+// sides, and restores the camelCase of known SVG and MathML attributes such as
+// viewBox, so lowercasing them again here would break foreign content.
 //
 //nolint:gocognit,funlen // HTML DOM conversion requires handling multiple node types.
 func convertToHTMLNode(n *html.Node, matchers map[string]string, parentPath string) *HTMLNode {
@@ -201,11 +314,15 @@ func convertToHTMLNode(n *html.Node, matchers map[string]string, parentPath stri
 			Attributes: make(map[string]any),
 		}
 
-		// Process attributes
+		// Process attributes; for duplicates the first value wins, as in browsers
 		for _, attr := range n.Attr {
-			node.Attributes[attr.Key] = resolveHTMLMatcherInValue(attr.Val, matchers)
+			if _, seen := node.Attributes[attr.Key]; !seen {
+				node.Attributes[attr.Key] = resolveHTMLMatcherInValue(attr.Val, matchers)
+			}
 		}
 
+		node.DuplicateAttributes = duplicateAttributes(n.Attr)
+
 		// Process children
 		childCounts := make(map[string]int)
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
@@ -314,11 +431,15 @@ func convertChildToHTMLNode(
 			Attributes: make(map[string]any),
 		}
 
-		// Process attributes
+		// Process attributes; for duplicates the first value wins, as in browsers
 		for _, attr := range n.Attr {
-			node.Attributes[attr.Key] = resolveHTMLMatcherInValue(attr.Val, matchers)
+			if _, seen := node.Attributes[attr.Key]; !seen {
+				node.Attributes[attr.Key] = resolveHTMLMatcherInValue(attr.Val, matchers)
+			}
 		}
 
+		node.DuplicateAttributes = duplicateAttributes(n.Attr)
+
 		// Process children recursively
 		nestedCounts := make(map[string]int)
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
@@ -0,0 +1,358 @@
+package testastic
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidSelector is returned when a selector cannot be parsed.
+var ErrInvalidSelector = errors.New("invalid selector")
+
+// htmlSelector is a parsed selector: compound selectors joined by combinators.
+type htmlSelector struct {
+	parts []selectorPart
+}
+
+// selectorPart is a compound selector and the combinator linking it to the previous part.
+type selectorPart struct {
+	child   bool // true for ">", false for descendant
+	tag     string
+	id      string
+	classes []string
+	attrs   []selectorAttr
+}
+
+// selectorAttr is an attribute condition such as [hidden] or [type="text"].
+type selectorAttr struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// parseSelector parses a simple CSS selector. Supported are tag names, *, #id, .class,
+// [attr] and [attr=value] in compound selectors, joined by descendant (space) and
+// child (>) combinators. Attribute values may be quoted to contain spaces or ">".
+func parseSelector(selector string) (*htmlSelector, error) {
+	tokens := tokenizeSelector(selector)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSelector, selector)
+	}
+
+	sel := &htmlSelector{}
+	child := false
+
+	for i, tok := range tokens {
+		if tok == ">" {
+			if i == 0 || i == len(tokens)-1 || child {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidSelector, selector)
+			}
+
+			child = true
+
+			continue
+		}
+
+		part, err := parseCompoundSelector(tok)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, selector)
+		}
+
+		part.child = child
+		child = false
+		sel.parts = append(sel.parts, part)
+	}
+
+	return sel, nil
+}
+
+// tokenizeSelector splits a selector into compound selectors and ">" combinators.
+// Whitespace and ">" inside [...] and quoted attribute values do not split.
+func tokenizeSelector(selector string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		inAttr  bool
+		quote   rune
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range selector {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case inAttr && (r == '"' || r == '\''):
+			quote = r
+		case r == '[':
+			inAttr = true
+		case r == ']':
+			inAttr = false
+		case !inAttr && r == '>':
+			flush()
+			tokens = append(tokens, ">")
+
+			continue
+		case !inAttr && unicode.IsSpace(r):
+			flush()
+
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	flush()
+
+	return tokens
+}
+
+// parseCompoundSelector parses a compound selector like div#main.card[hidden].
+//
+//nolint:cyclop // Each selector component needs its own branch.
+func parseCompoundSelector(s string) (selectorPart, error) {
+	var part selectorPart
+
+	end := strings.IndexAny(s, "#.[")
+	if end < 0 {
+		end = len(s)
+	}
+
+	if tag := s[:end]; tag != "*" {
+		part.tag = strings.ToLower(tag)
+	}
+
+	s = s[end:]
+
+	for s != "" {
+		switch s[0] {
+		case '#', '.':
+			next := strings.IndexAny(s[1:], "#.[")
+			if next < 0 {
+				next = len(s) - 1
+			}
+
+			name := s[1 : next+1]
+			if name == "" {
+				return part, ErrInvalidSelector
+			}
+
+			if s[0] == '#' {
+				part.id = name
+			} else {
+				part.classes = append(part.classes, name)
+			}
+
+			s = s[next+1:]
+
+		case '[':
+			closeIdx := closingBracket(s)
+			if closeIdx < 0 {
+				return part, ErrInvalidSelector
+			}
+
+			attr, err := parseSelectorAttr(s[1:closeIdx])
+			if err != nil {
+				return part, err
+			}
+
+			part.attrs = append(part.attrs, attr)
+			s = s[closeIdx+1:]
+
+		default:
+			return part, ErrInvalidSelector
+		}
+	}
+
+	return part, nil
+}
+
+// closingBracket returns the index of the "]" closing the attribute selector at the
+// start of s, skipping quoted values, or -1 if there is none.
+func closingBracket(s string) int {
+	var quote byte
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseSelectorAttr parses the inside of an attribute selector, e.g. type="text".
+func parseSelectorAttr(s string) (selectorAttr, error) {
+	name, value, hasValue := strings.Cut(s, "=")
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return selectorAttr{}, ErrInvalidSelector
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return selectorAttr{name: name, value: value, hasValue: hasValue}, nil
+}
+
+// selectHTML returns the elements under root matching sel, in document order.
+// The second return value holds each match's ancestors, outermost first.
+func selectHTML(root *HTMLNode, sel *htmlSelector) ([]*HTMLNode, [][]*HTMLNode) {
+	var (
+		matches   []*HTMLNode
+		ancestors [][]*HTMLNode
+		walk      func(node *HTMLNode, chain []*HTMLNode)
+	)
+
+	walk = func(node *HTMLNode, chain []*HTMLNode) {
+		if node == nil || node.Type != HTMLElement {
+			return
+		}
+
+		if node.Tag != "#document" {
+			if sel.matches(node, chain) {
+				matches = append(matches, node)
+				ancestors = append(ancestors, chain)
+			}
+
+			chain = append(chain[:len(chain):len(chain)], node)
+		}
+
+		for _, child := range node.Children {
+			walk(child, chain)
+		}
+	}
+
+	walk(root, nil)
+
+	return matches, ancestors
+}
+
+// Find returns the elements below n matching selector, in document order, e.g. to
+// count list items or pick a heading for a custom assertion. It supports the same
+// selectors as AssertVisible, such as tag names, #id and .class joined by descendant
+// and child combinators. A selector that cannot be parsed returns an error wrapping
+// ErrInvalidSelector, so a typo is not mistaken for an empty result.
+//
+// Example:
+//
+//	doc, _ := testastic.ParseHTML(resp.Body)
+//	items, err := doc.Find("ul.todo > li")
+//	testastic.NoError(t, err)
+//	testastic.Len(t, items, 3)
+func (n *HTMLNode) Find(selector string) ([]*HTMLNode, error) {
+	sel, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, _ := selectHTML(n, sel)
+	if len(matches) > 0 && matches[0] == n {
+		matches = matches[1:]
+	}
+
+	return matches, nil
+}
+
+// TextContent returns the text content of n and its descendants, concatenated in
+// document order, like the DOM property of the same name. Matchers in expected HTML
+// are rendered as their template expressions. Unlike the Text field, which is only set
+// on text nodes, it works on elements.
+func (n *HTMLNode) TextContent() string {
+	if n == nil {
+		return ""
+	}
+
+	if n.Type == HTMLText {
+		return getTextContent(n)
+	}
+
+	var sb strings.Builder
+
+	for _, child := range n.Children {
+		if child != nil && (child.Type == HTMLElement || child.Type == HTMLText) {
+			sb.WriteString(child.TextContent())
+		}
+	}
+
+	return sb.String()
+}
+
+// matches reports whether node, with the given ancestors, matches the selector.
+func (s *htmlSelector) matches(node *HTMLNode, ancestors []*HTMLNode) bool {
+	last := len(s.parts) - 1
+	if !s.parts[last].matches(node) {
+		return false
+	}
+
+	return s.matchAncestors(last, ancestors)
+}
+
+// matchAncestors matches parts[:i] against ancestors, honoring the combinator of parts[i].
+func (s *htmlSelector) matchAncestors(i int, ancestors []*HTMLNode) bool {
+	if i == 0 {
+		return true
+	}
+
+	prev := s.parts[i-1]
+
+	if s.parts[i].child {
+		n := len(ancestors) - 1
+
+		return n >= 0 && prev.matches(ancestors[n]) && s.matchAncestors(i-1, ancestors[:n])
+	}
+
+	for n := len(ancestors) - 1; n >= 0; n-- {
+		if prev.matches(ancestors[n]) && s.matchAncestors(i-1, ancestors[:n]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches reports whether a single element matches the compound selector.
+func (p selectorPart) matches(node *HTMLNode) bool {
+	if p.tag != "" && !strings.EqualFold(node.Tag, p.tag) {
+		return false
+	}
+
+	if p.id != "" && getString(node.Attributes["id"]) != p.id {
+		return false
+	}
+
+	classes := strings.Fields(getString(node.Attributes["class"]))
+	for _, c := range p.classes {
+		if !slices.Contains(classes, c) {
+			return false
+		}
+	}
+
+	for _, a := range p.attrs {
+		val, ok := node.Attributes[a.name]
+		if !ok || (a.hasValue && getString(val) != a.value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,7 @@
 package testastic_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -175,6 +176,32 @@ func TestAssertHTML_MatcherInAttribute(t *testing.T) {
 	}
 }
 
+func TestAssertHTML_AnyBoolishInAttribute(t *testing.T) {
+	// GIVEN: an expected HTML file with anyBoolish in an attribute
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<button aria-pressed="{{anyBoolish}}">Go</button>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	// WHEN: asserting boolean-like and other attribute values
+	for actual, wantFail := range map[string]bool{
+		`<button aria-pressed="true">Go</button>`:  false,
+		`<button aria-pressed="0">Go</button>`:     false,
+		`<button aria-pressed="mixed">Go</button>`: true,
+	} {
+		mt := &htmlMockT{}
+		testastic.AssertHTML(mt, expectedFile, actual)
+
+		// THEN: only boolean strings match
+		if mt.failed != wantFail {
+			t.Errorf("AssertHTML(%s) failed = %v, want %v", actual, mt.failed, wantFail)
+		}
+	}
+}
+
 func TestAssertHTML_MissingElement(t *testing.T) {
 	// GIVEN: an expected HTML file with two span elements
 	dir := t.TempDir()
@@ -367,6 +394,136 @@ func TestAssertHTML_PreserveWhitespace(t *testing.T) {
 	}
 }
 
+func TestAssertHTML_PreserveWhitespaceIn(t *testing.T) {
+	// GIVEN: an expected HTML file with a formatted code sample
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<div><pre><code>if x {
+    return
+}</code></pre><p>Hello World</p></div>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		actual     string
+		wantFailed bool
+	}{
+		{
+			name:   "whitespace differs outside pre",
+			actual: "<div><pre><code>if x {\n    return\n}</code></pre><p>Hello\n   World</p></div>",
+		},
+		{
+			name:       "whitespace differs inside pre",
+			actual:     "<div><pre><code>if x { return }</code></pre><p>Hello World</p></div>",
+			wantFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := &htmlMockT{}
+
+			// WHEN: asserting with whitespace preserved only in pre
+			testastic.AssertHTML(mt, expectedFile, tt.actual, testastic.PreserveWhitespaceIn("pre"))
+
+			// THEN: only whitespace inside pre is compared exactly
+			if mt.failed != tt.wantFailed {
+				t.Errorf("failed = %v, want %v: %s", mt.failed, tt.wantFailed, mt.message)
+			}
+		})
+	}
+}
+
+func TestAssertHTML_IgnoreTextMatching(t *testing.T) {
+	// GIVEN: an expected file with a relative timestamp and a matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<ul><li>3 minutes ago</li><li>{{regex ` + "`^[a-z]+$`" + `}}</li><li>Hello</li></ul>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		actual     string
+		wantFailed bool
+	}{
+		{
+			name:   "matching text differs",
+			actual: `<ul><li>2 hours ago</li><li>abc</li><li>Hello</li></ul>`,
+		},
+		{
+			name:       "other literal text differs",
+			actual:     `<ul><li>2 hours ago</li><li>abc</li><li>Bye</li></ul>`,
+			wantFailed: true,
+		},
+		{
+			name:       "matcher takes precedence",
+			actual:     `<ul><li>1 minutes ago</li><li>5 minutes ago</li><li>Hello</li></ul>`,
+			wantFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := &htmlMockT{}
+
+			// WHEN: asserting while ignoring relative timestamps
+			testastic.AssertHTML(mt, expectedFile, tt.actual,
+				testastic.IgnoreTextMatching(`\d+ (minute|hour)s ago`))
+
+			// THEN: only text matching the pattern is skipped
+			if mt.failed != tt.wantFailed {
+				t.Errorf("failed = %v, want %v: %s", mt.failed, tt.wantFailed, mt.message)
+			}
+		})
+	}
+}
+
+func TestAssertHTML_IgnoreTextMatching_InvalidPattern(t *testing.T) {
+	// GIVEN: an invalid text pattern
+	r := &recordingReporter{}
+
+	// WHEN: asserting with it
+	testastic.AssertHTML(r, "testdata/none.html", `<p>a</p>`, testastic.IgnoreTextMatching(`(`))
+
+	// THEN: a fatal pattern error is reported
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "invalid text pattern") {
+		t.Errorf("expected invalid text pattern error, got %v", r.fatals)
+	}
+}
+
+func TestSetDefaultHTMLOptions(t *testing.T) {
+	// GIVEN: default HTML options ignoring comments
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div><p>Hello</p></div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	testastic.SetDefaultHTMLOptions(testastic.IgnoreHTMLComments())
+	t.Cleanup(func() { testastic.SetDefaultHTMLOptions() })
+
+	// WHEN: asserting actual HTML with a comment and no call-site options
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<div><!-- build 42 --><p>Hello</p></div>`)
+
+	// THEN: the default applies
+	if mt.failed {
+		t.Errorf("expected pass with default options, got failure: %s", mt.message)
+	}
+}
+
 func TestAssertHTML_IgnoreComments(t *testing.T) {
 	// GIVEN: an expected HTML file with a comment
 	dir := t.TempDir()
@@ -391,6 +548,94 @@ func TestAssertHTML_IgnoreComments(t *testing.T) {
 	}
 }
 
+func TestAssertHTML_IgnoreDoctype(t *testing.T) {
+	// GIVEN: an expected HTML file with a doctype
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<!DOCTYPE html><html><head></head><body><p>Hello</p></body></html>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	actual := `<html><head></head><body><p>Hello</p></body></html>`
+
+	// WHEN: asserting output without a doctype
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, actual)
+
+	// THEN: the test fails by default
+	if !mt.failed {
+		t.Error("expected failure for missing doctype")
+	}
+
+	// WHEN: asserting with IgnoreDoctype
+	mt = &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.IgnoreDoctype())
+
+	// THEN: the test passes
+	if mt.failed {
+		t.Errorf("expected doctype to be ignored, got: %s", mt.message)
+	}
+
+	// WHEN: the actual output differs below the root
+	mt = &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<html><head></head><body><p>Bye</p></body></html>`, testastic.IgnoreDoctype())
+
+	// THEN: the difference below the unwrapped root is still reported
+	if !mt.failed {
+		t.Error("expected failure for changed text")
+	}
+}
+
+func TestAssertHTML_IgnoreHeadOrder(t *testing.T) {
+	// GIVEN: an expected document with several head elements
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<!DOCTYPE html><html><head>
+		<meta charset="utf-8">
+		<meta name="viewport" content="width=device-width">
+		<link rel="stylesheet" href="/app.css">
+	</head><body><p>Hello</p></body></html>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	reordered := `<!DOCTYPE html><html><head>
+		<link rel="stylesheet" href="/app.css">
+		<meta name="viewport" content="width=device-width">
+		<meta charset="utf-8">
+	</head><body><p>Hello</p></body></html>`
+
+	// WHEN: asserting reordered head elements with IgnoreHeadOrder or IgnoreChildOrderAt
+	for _, opt := range []testastic.HTMLOption{testastic.IgnoreHeadOrder(), testastic.IgnoreChildOrderAt("html > head")} {
+		mt := &htmlMockT{}
+		testastic.AssertHTML(mt, expectedFile, reordered, opt)
+
+		// THEN: the test passes
+		if mt.failed {
+			t.Errorf("expected head order to be ignored, got: %s", mt.message)
+		}
+	}
+
+	// WHEN: a head element is missing
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<!DOCTYPE html><html><head>
+		<link rel="stylesheet" href="/app.css">
+		<meta charset="utf-8">
+	</head><body><p>Hello</p></body></html>`, testastic.IgnoreHeadOrder())
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected failure for missing meta element")
+	}
+}
+
 func TestAssertHTML_IgnoreElements(t *testing.T) {
 	// GIVEN: an expected HTML file with a script element
 	dir := t.TempDir()
@@ -439,6 +684,103 @@ func TestAssertHTML_IgnoreAttributes(t *testing.T) {
 	}
 }
 
+func TestAssertHTML_IgnoreAttributesMatching(t *testing.T) {
+	// GIVEN: an expected HTML file with data-* and aria-* attributes
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<div id="card" data-testid="a" aria-label="Card"><span data-x="1">Content</span></div>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<div id="card" data-testid="b" aria-hidden="true"><span data-y="2">Content</span></div>`
+
+	// WHEN: asserting with IgnoreAttributesMatching
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.IgnoreAttributesMatching("data-*", "aria-*"))
+
+	// THEN: the test passes (matching attributes are ignored)
+	if mt.failed {
+		t.Errorf("expected matching attributes to be ignored, got: %s", mt.message)
+	}
+
+	// WHEN: a non-matching attribute differs
+	mt = &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, strings.Replace(actual, `id="card"`, `id="other"`, 1),
+		testastic.IgnoreAttributesMatching("data-*", "aria-*"))
+
+	// THEN: the test fails (patterns are anchored to the whole name)
+	if !mt.failed {
+		t.Error("expected failure for differing id attribute")
+	}
+}
+
+func TestAssertHTML_IgnoreAttributesMatching_InvalidPattern(t *testing.T) {
+	// GIVEN: an expected HTML file
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div>Content</div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting with a malformed pattern
+	testastic.AssertHTML(r, expectedFile, `<div>Content</div>`, testastic.IgnoreAttributesMatching("data-["))
+
+	// THEN: the assertion fails fatally with a pattern error
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "invalid attribute pattern") {
+		t.Errorf("expected fatal pattern error, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertHTML_DetectDuplicateAttributes(t *testing.T) {
+	// GIVEN: an expected HTML file with a single class attribute
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div class="a">Content</div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	actual := `<div class="a" class="b">Content</div>`
+
+	// WHEN: asserting markup with a duplicate attribute without the option
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, actual)
+
+	// THEN: the duplicate is not reported
+	if mt.failed {
+		t.Errorf("expected duplicate to be ignored without option, got: %s", mt.message)
+	}
+
+	// WHEN: asserting with DetectDuplicateAttributes
+	r := &recordingReporter{}
+	testastic.AssertHTML(r, expectedFile, actual, testastic.DetectDuplicateAttributes())
+
+	// THEN: the duplicate is reported
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "attribute class appears 2 times") {
+		t.Errorf("expected duplicate attribute error, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+
+	// WHEN: comparing the same markup
+	diffs, err := testastic.CompareHTML([]byte(`<div class="a">Content</div>`), []byte(actual),
+		testastic.DetectDuplicateAttributes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN: the first value is kept, as in browsers, and only the duplicate is reported
+	testastic.Len(t, diffs, 1)
+	testastic.Contains(t, diffs[0].Reason, "appears 2 times")
+}
+
 func TestAssertHTML_CreateExpectedFile(t *testing.T) {
 	// GIVEN: a non-existent expected file path
 	dir := t.TempDir()
@@ -648,6 +990,49 @@ func TestFormatHTMLDiffInline(t *testing.T) {
 	}
 }
 
+func TestFormatHTMLDiffHTML(t *testing.T) {
+	// GIVEN: expected and actual HTML nodes with different text content
+	expected := &testastic.HTMLNode{
+		Type: testastic.HTMLElement,
+		Tag:  "div",
+		Children: []*testastic.HTMLNode{
+			{
+				Type:     testastic.HTMLElement,
+				Tag:      "span",
+				Children: []*testastic.HTMLNode{{Type: testastic.HTMLText, Text: "Alice"}},
+			},
+		},
+	}
+
+	actual := &testastic.HTMLNode{
+		Type: testastic.HTMLElement,
+		Tag:  "div",
+		Children: []*testastic.HTMLNode{
+			{
+				Type:     testastic.HTMLElement,
+				Tag:      "span",
+				Children: []*testastic.HTMLNode{{Type: testastic.HTMLText, Text: "Bob"}},
+			},
+		},
+	}
+
+	// WHEN: formatting the diff as HTML
+	result := testastic.FormatHTMLDiffHTML(expected, actual)
+
+	// THEN: changed nodes are escaped and wrapped in del/ins markup
+	if !strings.Contains(result, "<del>-   &lt;span&gt;Alice&lt;/span&gt;</del>") {
+		t.Errorf("expected removed line in <del>, got:\n%s", result)
+	}
+
+	if !strings.Contains(result, "<ins>+   &lt;span&gt;Bob&lt;/span&gt;</ins>") {
+		t.Errorf("expected added line in <ins>, got:\n%s", result)
+	}
+
+	if !strings.Contains(result, "  &lt;div&gt;") {
+		t.Errorf("expected unchanged line to be kept, got:\n%s", result)
+	}
+}
+
 func TestAssertHTML_EmbeddedMatcherInAttribute(t *testing.T) {
 	// GIVEN: an expected HTML file with embedded matcher in attribute.
 	dir := t.TempDir()
@@ -936,23 +1321,743 @@ func TestAssertHTML_EmbeddedOneOf_Mismatch(t *testing.T) {
 	}
 }
 
-// htmlMockT is a mock testing.TB for testing HTML assertions.
-type htmlMockT struct {
-	testing.TB
-	failed  bool
-	message string
-}
+func TestAssertHTML_TreatNbspAsSpace(t *testing.T) {
+	// GIVEN: an expected HTML file with a regular space
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
 
-func (m *htmlMockT) Helper() {}
+	expected := `<div><span>Hello World</span></div>`
 
-func (m *htmlMockT) Fatalf(format string, args ...any) {
-	m.failed = true
-	m.message = format
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<div><span>Hello&nbsp;World</span></div>`
+
+	// WHEN: asserting with TreatNbspAsSpace while preserving whitespace
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.PreserveWhitespace(), testastic.TreatNbspAsSpace())
+
+	// THEN: the test passes (the non-breaking space compares as a space)
+	if mt.failed {
+		t.Errorf("expected &nbsp; to be treated as a space, got: %s", mt.message)
+	}
 }
 
-func (m *htmlMockT) Errorf(format string, args ...any) {
-	m.failed = true
-	m.message = format
+func TestAssertHTML_NbspWithoutOption(t *testing.T) {
+	// GIVEN: an expected HTML file with a regular space
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<div><span>Hello World</span></div>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<div><span>Hello&nbsp;World</span></div>`
+
+	// WHEN: asserting with PreserveWhitespace but without TreatNbspAsSpace
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.PreserveWhitespace())
+
+	// THEN: the test fails (the non-breaking space is a distinct character)
+	if !mt.failed {
+		t.Error("expected failure when &nbsp; is not normalized")
+	}
 }
 
-func (m *htmlMockT) Logf(format string, args ...any) {}
+func TestAssertHTML_WithNotMatcher(t *testing.T) {
+	// GIVEN: an expected HTML file with a negated oneOf matcher in an attribute
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<div class="{{not oneOf "hidden" "disabled"}}">Content</div>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+
+	// WHEN: asserting with an allowed and a disallowed class
+	testastic.AssertHTML(mt, expectedFile, `<div class="visible">Content</div>`)
+
+	// THEN: the allowed class passes
+	if mt.failed {
+		t.Errorf("expected no failure, got: %s", mt.message)
+	}
+
+	testastic.AssertHTML(mt, expectedFile, `<div class="hidden">Content</div>`)
+
+	// THEN: the disallowed class fails
+	if !mt.failed {
+		t.Error("expected failure for negated oneOf value")
+	}
+}
+
+func TestCompareHTML_NotMatcherInTemplate(t *testing.T) {
+	// GIVEN: negated matchers embedded in text with literal parts
+	tests := []struct {
+		name      string
+		expected  string
+		actual    string
+		wantDiffs int
+	}{
+		{"allowed value", `<p>Hi {{not oneOf "bob"}}</p>`, `<p>Hi alice</p>`, 0},
+		{"negated value", `<p>Hi {{not oneOf "bob"}}</p>`, `<p>Hi bob</p>`, 1},
+		{"negated type", `<p>Count {{not anyInt}}</p>`, `<p>Count 42</p>`, 1},
+		{"allOf with not", "<p>Id {{allOf (regex `^[a-z]+$`) (not oneOf \"root\")}}</p>", `<p>Id root</p>`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN: comparing
+			diffs, err := testastic.CompareHTML([]byte(tt.expected), []byte(tt.actual))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// THEN: the matcher is checked against the text between the literals
+			testastic.Len(t, diffs, tt.wantDiffs)
+		})
+	}
+}
+
+func TestCompareHTML_StringMatchersInTemplate(t *testing.T) {
+	// GIVEN: matchers without a regex form embedded in text and class lists
+	tests := []struct {
+		name      string
+		expected  string
+		actual    string
+		wantDiffs int
+	}{
+		{"anyBoolish match", `<p>Count {{anyBoolish}}</p>`, `<p>Count true</p>`, 0},
+		{"anyBoolish mismatch", `<p>Count {{anyBoolish}}</p>`, `<p>Count banana</p>`, 1},
+		{"anySemver match", `<p>Version {{anySemver}} installed</p>`, `<p>Version 1.2.3 installed</p>`, 0},
+		{"anySemver mismatch", `<p>Version {{anySemver}} installed</p>`, `<p>Version latest installed</p>`, 1},
+		{"hasClass match", `<div class="btn {{hasClass "active"}}"></div>`, `<div class="active btn"></div>`, 0},
+		{"hasClass mismatch", `<div class="btn {{hasClass "active"}}"></div>`, `<div class="btn banana"></div>`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN: comparing
+			diffs, err := testastic.CompareHTML([]byte(tt.expected), []byte(tt.actual))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// THEN: the embedded matcher is checked, not treated as matching anything
+			testastic.Len(t, diffs, tt.wantDiffs)
+		})
+	}
+}
+
+func TestCompareHTML_RegexiInTemplate(t *testing.T) {
+	// GIVEN: a case-insensitive regex embedded in text and in an attribute
+	expected := []byte("<p title=\"id-{{regexi `[a-f]+`}}\">Hi {{regexi `wor.d`}}</p>")
+
+	// WHEN: comparing against text in a different case
+	diffs, err := testastic.CompareHTML(expected, []byte(`<p title="id-ABC">Hi WORLD</p>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN: the regex ignores case like a standalone {{regexi}}
+	testastic.Len(t, diffs, 0)
+
+	// WHEN: comparing with a plain regex containing an alternation
+	diffs, err = testastic.CompareHTML([]byte("<p>Hi {{regex `a|b`}}!</p>"), []byte(`<p>Hi a and b!</p>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN: the alternation stays within the matcher's part
+	testastic.Len(t, diffs, 1)
+}
+
+func TestAssertHTML_SortChildrenBy(t *testing.T) {
+	// GIVEN: an expected HTML list sorted by data-key
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<ul><li data-key="a">Apple</li><li data-key="b">Banana</li><li data-key="c">Cherry</li></ul>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<ul><li data-key="c">Cherry</li><li data-key="a">Apple</li><li data-key="b">Banana</li></ul>`
+
+	// WHEN: asserting with SortChildrenBy on the list
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.SortChildrenBy("html > body > ul", "data-key"))
+
+	// THEN: the test passes (children are compared in key order)
+	if mt.failed {
+		t.Errorf("expected sorted children to match, got: %s", mt.message)
+	}
+}
+
+func TestAssertHTML_SortChildrenBy_ContentMismatch(t *testing.T) {
+	// GIVEN: an expected HTML list and a reordered actual list with changed content
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<ul><li data-key="a">Apple</li><li data-key="b">Banana</li></ul>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<ul><li data-key="b">Blueberry</li><li data-key="a">Apple</li></ul>`
+
+	// WHEN: asserting with SortChildrenBy on the list
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.SortChildrenBy("html > body > ul", "data-key"))
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected failure for changed content")
+	}
+}
+
+// htmlMockT is a mock testing.TB for testing HTML assertions.
+type htmlMockT struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (m *htmlMockT) Helper() {}
+
+func (m *htmlMockT) Fatalf(format string, args ...any) {
+	m.failed = true
+	m.message = format
+}
+
+func (m *htmlMockT) Errorf(format string, args ...any) {
+	m.failed = true
+	m.message = format
+}
+
+func (m *htmlMockT) Logf(format string, args ...any) {}
+
+func TestAssertHTML_HasClassMatcher(t *testing.T) {
+	// GIVEN: an expected HTML file requiring a class in the class attribute
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<button class="{{hasClass "active"}}">Save</button>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+
+	// WHEN: asserting with the class among others
+	testastic.AssertHTML(mt, expectedFile, `<button class="btn btn-primary active">Save</button>`)
+
+	// THEN: the assertion passes
+	if mt.failed {
+		t.Errorf("expected no failure, got: %s", mt.message)
+	}
+
+	// WHEN: asserting without the class
+	testastic.AssertHTML(mt, expectedFile, `<button class="btn btn-primary inactive">Save</button>`)
+
+	// THEN: the assertion fails
+	if !mt.failed {
+		t.Error("expected failure when the required class is missing")
+	}
+}
+
+func TestAssertHTML_ClassOrderIgnored(t *testing.T) {
+	// GIVEN: an expected HTML file with a class attribute
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div class="btn btn-primary active">Save</div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<div class="active  btn-primary btn">Save</div>`
+
+	// WHEN: asserting against the same classes in a different order
+	testastic.AssertHTML(mt, expectedFile, actual)
+
+	// THEN: the test passes
+	if mt.failed {
+		t.Errorf("expected class order to be ignored, got: %s", mt.message)
+	}
+}
+
+func TestAssertHTML_StrictClassOrder(t *testing.T) {
+	// GIVEN: an expected HTML file with a class attribute
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div class="btn active">Save</div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	mt := &htmlMockT{}
+	actual := `<div class="active btn">Save</div>`
+
+	// WHEN: asserting with StrictClassOrder
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.StrictClassOrder())
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected failure for reordered classes with StrictClassOrder")
+	}
+}
+
+func TestAssertHTML_ClassSetWithEmbeddedMatcher(t *testing.T) {
+	// GIVEN: an expected class attribute with an embedded matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div class="btn btn-{{oneOf "primary" "secondary"}}">Save</div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	// WHEN: asserting against reordered classes that satisfy the matcher
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<div class="btn-secondary btn">Save</div>`)
+
+	// THEN: the test passes
+	if mt.failed {
+		t.Errorf("expected embedded matcher to match within class set, got: %s", mt.message)
+	}
+
+	// WHEN: asserting against a class that does not satisfy the matcher
+	mt = &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<div class="btn-danger btn">Save</div>`)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected failure for class not matching embedded matcher")
+	}
+}
+
+func TestAssertHTMLContains(t *testing.T) {
+	// GIVEN: an expected fragment with a matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "alert.fragment.html")
+
+	expected := `<div class="alert"><strong>{{anyString}}</strong> saved</div>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	actual := `<html><body>
+		<nav><a href="/">Home</a></nav>
+		<main><section><div class="alert"><strong>Profile</strong> saved</div></section></main>
+	</body></html>`
+
+	// WHEN: asserting the fragment is contained in the page
+	// THEN: the assertion passes
+	testastic.AssertHTMLContains(t, expectedFile, actual)
+}
+
+func TestAssertHTMLContains_NotFound(t *testing.T) {
+	// GIVEN: an expected fragment that does not appear in the page
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "alert.fragment.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div class="alert">Saved</div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting against a page with a similar but different element
+	testastic.AssertHTMLContains(r, expectedFile, `<main><p>Intro</p><div class="alert">Failed</div></main>`)
+
+	// THEN: the failure names the closest candidate
+	if len(r.errors) != 1 {
+		t.Fatalf("expected 1 error, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+
+	if !strings.Contains(r.errors[0], "closest candidate at html > body > main > div") {
+		t.Errorf("expected closest candidate in message, got: %s", r.errors[0])
+	}
+}
+
+func TestAssertHTMLContains_MultipleRoots(t *testing.T) {
+	// GIVEN: an expected fragment with two root elements
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "invalid.fragment.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<p>One</p><p>Two</p>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting the fragment
+	testastic.AssertHTMLContains(r, expectedFile, `<p>One</p><p>Two</p>`)
+
+	// THEN: the invalid fragment is reported as fatal
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "single root element") {
+		t.Errorf("expected fatal about single root element, got %v", r.fatals)
+	}
+}
+
+func TestAssertVisibleAndHidden(t *testing.T) {
+	// GIVEN: markup with elements hidden in different ways
+	actual := `<html><body>
+		<form id="login"><button type="submit" class="btn primary">Sign in</button></form>
+		<div id="banner" hidden>Error</div>
+		<div aria-hidden="true"><span class="icon">x</span></div>
+		<p id="note" style="color: red; display: none !important">Note</p>
+	</body></html>`
+
+	// WHEN: asserting visibility of matching elements
+	// THEN: visible and hidden elements are recognized
+	testastic.AssertVisible(t, actual, "form#login > button[type=submit].btn")
+	testastic.AssertHidden(t, actual, "#banner")
+	testastic.AssertHidden(t, actual, "div span.icon")
+	testastic.AssertHidden(t, actual, "p#note")
+}
+
+func TestAssertVisible_Fail(t *testing.T) {
+	// GIVEN: an element hidden by an ancestor
+	actual := `<div aria-hidden="true"><span class="icon">x</span></div>`
+	r := &recordingReporter{}
+
+	// WHEN: asserting it is visible
+	testastic.AssertVisible(r, actual, ".icon")
+
+	// THEN: the failure names the hiding ancestor
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `aria-hidden="true" on ancestor`) {
+		t.Errorf("expected failure naming hidden ancestor, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertHidden_NoMatch(t *testing.T) {
+	// GIVEN: a selector that matches nothing
+	r := &recordingReporter{}
+
+	// WHEN: asserting hidden
+	testastic.AssertHidden(r, `<div></div>`, "#missing")
+
+	// THEN: the assertion fails
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "no matching element") {
+		t.Errorf("expected no-match failure, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertVisible_InvalidSelector(t *testing.T) {
+	// GIVEN: a malformed selector
+	r := &recordingReporter{}
+
+	// WHEN: asserting visibility
+	testastic.AssertVisible(r, `<div></div>`, "div[id")
+
+	// THEN: a fatal selector error is reported
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "invalid selector") {
+		t.Errorf("expected invalid selector error, got %v", r.fatals)
+	}
+}
+
+func TestAssertVisible_QuotedAttributeValues(t *testing.T) {
+	// GIVEN: elements whose attribute values contain spaces, ">" and "]"
+	actual := `<div title="a b"><a title="x>y">link</a><span data-range="[1, 2]">r</span></div>`
+
+	// WHEN: selecting them by quoted attribute values
+	// THEN: the values are kept whole and the elements are found
+	testastic.AssertVisible(t, actual, `div[title="a b"]`)
+	testastic.AssertVisible(t, actual, `a[title="x>y"]`)
+	testastic.AssertVisible(t, actual, `div[title='a b'] > a[title="x>y"]`)
+	testastic.AssertVisible(t, actual, `span[data-range="[1, 2]"]`)
+
+	// WHEN: a quoted value does not match
+	r := &recordingReporter{}
+	testastic.AssertVisible(r, actual, `div[title="a  b"]`)
+
+	// THEN: no element is found
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "no matching element") {
+		t.Errorf("expected no-match failure, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertHTMLAll(t *testing.T) {
+	// GIVEN: a form where one input lacks a name attribute
+	actual := `<form><input name="user"><input type="password"><input name="code"></form>`
+	hasName := func(n *testastic.HTMLNode) bool {
+		_, ok := n.Attributes["name"]
+
+		return ok
+	}
+
+	// WHEN: auditing inputs that have a name
+	// THEN: the assertion passes
+	testastic.AssertHTMLAll(t, actual, "input[name]", hasName)
+
+	// WHEN: auditing all inputs
+	r := &recordingReporter{}
+	testastic.AssertHTMLAll(r, actual, "form input", hasName)
+
+	// THEN: the failing input is reported with its path
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "not satisfied at html > body > form > input[1]") {
+		t.Errorf("expected failure for the second input, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestHTMLNode_FindAndText(t *testing.T) {
+	// GIVEN: a parsed document with a heading and a list
+	doc, err := testastic.ParseHTML([]byte(`<main id="app"><h1 class="title">Hello <em>World</em></h1>
+		<ul class="todo"><li>a</li><li class="done">b</li><li>c</li></ul></main>`))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	// WHEN: finding elements by tag, class, id and combinators
+	// THEN: the matching elements are returned in document order
+	testastic.Len(t, mustFind(t, doc, "li"), 3)
+	testastic.Len(t, mustFind(t, doc, "ul.todo > li.done"), 1)
+	testastic.Len(t, mustFind(t, doc, "#app h1"), 1)
+	testastic.Len(t, mustFind(t, doc, "table"), 0)
+
+	// WHEN: finding from an element that itself matches the selector
+	list := mustFind(t, doc, "ul")[0]
+
+	// THEN: only descendants are returned
+	testastic.Len(t, mustFind(t, list, "ul"), 0)
+	testastic.Equal(t, "b", mustFind(t, list, "li")[1].TextContent())
+
+	// WHEN: reading the text of an element with nested markup
+	// THEN: the descendant text is concatenated
+	testastic.Equal(t, "Hello World", mustFind(t, doc, ".title")[0].TextContent())
+}
+
+func TestHTMLNode_FindInvalidSelector(t *testing.T) {
+	// GIVEN: a parsed document
+	doc, err := testastic.ParseHTML([]byte(`<ul><li class="a">a</li></ul>`))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	// WHEN: finding with malformed selectors
+	for _, selector := range []string{"li[class", "", "ul >", "li..a"} {
+		nodes, err := doc.Find(selector)
+
+		// THEN: an error is returned instead of an empty result
+		if !errors.Is(err, testastic.ErrInvalidSelector) {
+			t.Errorf("expected ErrInvalidSelector for %q, got %v", selector, err)
+		}
+
+		testastic.Len(t, nodes, 0)
+	}
+}
+
+// mustFind returns the elements below n matching selector, failing the test on an error.
+func mustFind(t *testing.T, n *testastic.HTMLNode, selector string) []*testastic.HTMLNode {
+	t.Helper()
+
+	nodes, err := n.Find(selector)
+	if err != nil {
+		t.Fatalf("failed to find %q: %v", selector, err)
+	}
+
+	return nodes
+}
+
+func TestAssertHTML_PresentAttribute(t *testing.T) {
+	// GIVEN: an expected file requiring an attribute with any value
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<div><img src="a.png" alt="{{present}}"></div>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	// WHEN: the attribute has a value, is empty, or is missing
+	// THEN: only the missing attribute fails, as removed
+	testastic.AssertHTML(t, expectedFile, `<div><img src="a.png" alt="A cat"></div>`)
+	testastic.AssertHTML(t, expectedFile, `<div><img src="a.png" alt></div>`)
+
+	diffs, err := testastic.CompareHTML([]byte(`<div><img src="a.png" alt="{{present}}"></div>`),
+		[]byte(`<div><img src="a.png"></div>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "html > body > div > img @alt", diffs[0].Path)
+	testastic.Equal(t, testastic.DiffRemoved, diffs[0].Type)
+}
+
+func TestRequireAttributes(t *testing.T) {
+	// GIVEN: actual HTML where the second image lacks alt
+	expected := []byte(`<div><img src="a.png" alt="A"><img src="b.png"><a href="/">x</a></div>`)
+	actual := []byte(`<div><img src="a.png" alt="A"><img src="b.png"><a href="/">x</a></div>`)
+
+	// WHEN: comparing with alt required on images
+	diffs, err := testastic.CompareHTML(expected, actual, testastic.RequireAttributes("IMG", "alt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN: the missing attribute is reported, even though expected and actual are equal
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "html > body > div > img[1] @alt", diffs[0].Path)
+	testastic.Equal(t, testastic.DiffRemoved, diffs[0].Type)
+	testastic.Contains(t, diffs[0].Reason, "<img> elements require the alt attribute")
+
+	// WHEN: updating the expected file with the same actual HTML
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	writeErr := os.WriteFile(expectedFile, []byte(`<div><img src="a.png"></div>`), 0o644)
+	if writeErr != nil {
+		t.Fatalf("failed to create expected file: %v", writeErr)
+	}
+
+	r := &recordingReporter{}
+	testastic.AssertHTML(r, expectedFile, string(actual), testastic.HTMLUpdate(), testastic.RequireAttributes("img", "alt"))
+
+	// THEN: the missing attribute still fails the assertion
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], "require the alt attribute")
+}
+
+func TestAssertHTML_AttributeNameCase(t *testing.T) {
+	// GIVEN: an expected file whose attribute names differ in case from actual,
+	// including an SVG attribute written in lowercase
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<div dataValue="1" Title="x"><svg viewbox="0 0 1 1"></svg></div>`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	actual := `<div datavalue="1" title="y"><svg viewBox="0 0 1 1"></svg></div>`
+
+	// WHEN: ignoring the differing attribute by a camelCase name
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, actual,
+		testastic.IgnoreAttributeAt("html > body > div@TITLE"))
+
+	// THEN: attribute names are compared case-insensitively
+	if mt.failed {
+		t.Errorf("expected pass, got failure: %s", mt.message)
+	}
+}
+
+func TestCompareHTML(t *testing.T) {
+	// GIVEN: expected HTML with a matcher and non-matching actual HTML
+	expected := []byte(`<div id="{{anyString}}"><p>Hello</p><span>a</span></div>`)
+	actual := []byte(`<div id="main"><p>Goodbye</p><span>a</span></div>`)
+
+	// WHEN: comparing without a testing.TB
+	diffs, err := testastic.CompareHTML(expected, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: only the differing text is reported
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 difference, got %d: %v", len(diffs), diffs)
+	}
+
+	if !strings.Contains(diffs[0].Path, "p") {
+		t.Errorf("expected difference at the paragraph, got %s", diffs[0].Path)
+	}
+}
+
+func TestCompareHTML_InvalidOption(t *testing.T) {
+	// GIVEN: an invalid attribute pattern
+	// WHEN: comparing
+	_, err := testastic.CompareHTML([]byte(`<p></p>`), []byte(`<p></p>`),
+		testastic.IgnoreAttributesMatching("["))
+
+	// THEN: the option error is returned
+	if !errors.Is(err, testastic.ErrInvalidAttributePattern) {
+		t.Errorf("expected ErrInvalidAttributePattern, got %v", err)
+	}
+}
+
+func TestAssertHTML_NormalizeAttributeWhitespace(t *testing.T) {
+	// GIVEN: attribute values that differ only in formatting, one with an embedded matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	expected := `<img style="color: red; border-left: 6px solid {{anyString}}" srcset="a.jpg 1x, b.jpg 2x">`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	actual := `<img style=" color:red;border-left:6px  solid blue " srcset="a.jpg 1x,b.jpg   2x">`
+
+	// WHEN: asserting without the option
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, actual)
+
+	// THEN: the formatting differences fail
+	if !mt.failed {
+		t.Error("expected failure for differently formatted attributes")
+	}
+
+	// WHEN: asserting with NormalizeAttributeWhitespace for the attributes
+	mt = &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, actual, testastic.NormalizeAttributeWhitespace("style", "srcset"))
+
+	// THEN: the test passes
+	if mt.failed {
+		t.Errorf("expected pass, got failure: %s", mt.message)
+	}
+}
+
+func TestAssertHTML_ContainsMatcherInText(t *testing.T) {
+	// GIVEN: an expected file asserting only part of an element's text
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "expected.html")
+
+	err := os.WriteFile(expectedFile, []byte(`<p class="alert">{{contains "expired"}}</p>`), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create expected file: %v", err)
+	}
+
+	// WHEN: the text contains the substring
+	mt := &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<p class="alert">Your session has expired.</p>`)
+
+	// THEN: the test passes
+	if mt.failed {
+		t.Errorf("expected pass, got failure: %s", mt.message)
+	}
+
+	// WHEN: the text does not contain it
+	mt = &htmlMockT{}
+	testastic.AssertHTML(mt, expectedFile, `<p class="alert">Welcome back.</p>`)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected failure for text without the substring")
+	}
+}
@@ -0,0 +1,214 @@
+package testastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AssertJSONStream compares a stream of concatenated JSON documents, such as
+// {...}{...}{...}, against an expected file containing a stream of documents.
+// Documents are compared pairwise in order, or regardless of order with
+// IgnoreStreamOrder(). Each expected document supports template matchers.
+// Diff paths include the document index, e.g. $[1].name for the second document.
+// T can be: []byte, string, or io.Reader.
+//
+// Example:
+//
+//	testastic.AssertJSONStream(t, "testdata/events.expected.json", output)
+//
+//nolint:funlen // Main assertion flow needs sequential validation steps.
+func AssertJSONStream[T any](tb Reporter, expectedFile string, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+
+	actualDocs, err := decodeJSONStream(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	if cfg.Record {
+		recordErr := writeJSONStreamFile(expectedFile, actualBytes, nil, nil)
+		if recordErr != nil {
+			tb.Fatalf("testastic: failed to record expected file: %v", recordErr)
+		}
+
+		recordUpdate("recorded", expectedFile)
+		tb.Logf("testastic: recorded expected file %s", expectedFile)
+
+		return
+	}
+
+	_, statErr := os.Stat(expectedFile)
+	if os.IsNotExist(statErr) {
+		if cfg.Update {
+			createErr := writeJSONStreamFile(expectedFile, actualBytes, nil, nil)
+			if createErr != nil {
+				tb.Fatalf("testastic: failed to create expected file: %v", createErr)
+			}
+
+			recordUpdate("created", expectedFile)
+			tb.Logf("testastic: created expected file %s", expectedFile)
+
+			return
+		}
+
+		tb.Fatalf(
+			"testastic: expected file does not exist: %s (run with -update to create)",
+			expectedFile,
+		)
+
+		return
+	}
+
+	content, err := os.ReadFile(expectedFile) //nolint:gosec // Path is controlled by test code.
+	if err != nil {
+		tb.Fatalf("testastic: failed to read expected file: %v", err)
+
+		return
+	}
+
+	expected, err := parseExpectedStream(string(content), cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, expected.UnorderedPaths...)
+
+	expectedDocs, _ := expected.Data.([]any)
+	diffs := compareJSONStream(expectedDocs, actualDocs, cfg)
+
+	if cfg.Update && len(diffs) > 0 {
+		updateErr := writeJSONStreamFile(
+			expectedFile, actualBytes, expected.ExtractMatcherPositions(), expectedKeyOrder(expected.Raw, true),
+		)
+		if updateErr != nil {
+			tb.Fatalf("testastic: failed to update expected file: %v", updateErr)
+		}
+
+		recordUpdate("updated", expectedFile)
+		tb.Logf("testastic: updated expected file %s", expectedFile)
+
+		return
+	}
+
+	if len(diffs) > 0 {
+		reportJSONFailure(tb, cfg, "AssertJSONStream ("+expectedFile+")", expectedDocs, actualDocs, diffs)
+	}
+}
+
+// compareJSONStream compares documents as the elements of a top-level array, so the
+// document index is part of each diff path.
+func compareJSONStream(expected, actual []any, cfg *Config) []Difference {
+	cfg.refs = nil
+
+	var diffs []Difference
+
+	if cfg.IgnoreStreamOrder {
+		diffs = compareArraysUnordered(expected, actual, "$", cfg)
+	} else {
+		diffs = compareArraysOrdered(expected, actual, "$", cfg)
+	}
+
+	return append(diffs, cfg.resolveRefs()...)
+}
+
+// parseExpectedStream parses a stream of expected JSON documents with template expressions.
+// The returned Data is a []any holding one element per document.
+func parseExpectedStream(content string, useNumber bool) (*ExpectedJSON, error) {
+	expected := &ExpectedJSON{
+		Matchers: make(map[string]string),
+		Raw:      content,
+	}
+
+	docs, err := decodeJSONStream([]byte(substituteTemplateExprs(content, expected)), useNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected file as JSON stream: %w", err)
+	}
+
+	for i, doc := range docs {
+		docs[i], err = replacePlaceholders(doc, fmt.Sprintf("$[%d]", i), expected)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	expected.Data = docs
+
+	return expected, nil
+}
+
+// decodeJSONStream decodes successive JSON documents until the end of data.
+func decodeJSONStream(data []byte, useNumber bool) ([]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if useNumber {
+		dec.UseNumber()
+	}
+
+	var docs []any
+
+	for {
+		var doc any
+
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON in document %d: %w", len(docs), err)
+		}
+
+		docs = append(docs, doc)
+	}
+}
+
+// writeJSONStreamFile writes the documents of actual as pretty-printed JSON separated by
+// newlines, preserving matchers at the given positions, object keys in the given order
+// and numbers as written.
+func writeJSONStreamFile(path string, actual []byte, matcherPositions map[string]string, keyOrder map[string][]string) error {
+	docs, err := decodeJSONStream(actual, true)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	for i, doc := range docs {
+		formatted, genErr := generateUpdatedJSON(doc, fmt.Sprintf("$[%d]", i), matcherPositions, keyOrder)
+		if genErr != nil {
+			return fmt.Errorf("failed to generate updated JSON: %w", genErr)
+		}
+
+		sb.WriteString(formatted)
+	}
+
+	mkdirErr := os.MkdirAll(filepath.Dir(path), dirPerm)
+	if mkdirErr != nil {
+		return fmt.Errorf("failed to create directory: %w", mkdirErr)
+	}
+
+	writeErr := os.WriteFile(path, []byte(sb.String()), filePerm)
+	if writeErr != nil {
+		return fmt.Errorf("failed to write expected file: %w", writeErr)
+	}
+
+	return nil
+}
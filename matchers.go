@@ -1,19 +1,35 @@
 package testastic
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Matcher parsing errors.
 var (
-	ErrInvalidRegexSyntax = errors.New("invalid regex syntax")
-	ErrInvalidOneOfSyntax = errors.New("invalid oneOf syntax")
-	ErrUnknownMatcher     = errors.New("unknown matcher")
+	ErrInvalidRegexSyntax         = errors.New("invalid regex syntax")
+	ErrInvalidOneOfSyntax         = errors.New("invalid oneOf syntax")
+	ErrInvalidTimestampSyntax     = errors.New("invalid anyTimestamp syntax")
+	ErrInvalidNotSyntax           = errors.New("invalid not syntax")
+	ErrInvalidAllOfSyntax         = errors.New("invalid allOf syntax")
+	ErrInvalidNumberBetweenSyntax = errors.New("invalid numberBetween syntax")
+	ErrInvalidHasClassSyntax      = errors.New("invalid hasClass syntax")
+	ErrInvalidTypeOneOfSyntax     = errors.New("invalid typeOneOf syntax")
+	ErrInvalidTypeSyntax          = errors.New("invalid type syntax")
+	ErrInvalidCaptureSyntax       = errors.New("invalid capture syntax")
+	ErrInvalidRefSyntax           = errors.New("invalid ref syntax")
+	ErrInvalidArrayLenSyntax      = errors.New("invalid arrayLen syntax")
+	ErrInvalidHasPrefixSyntax     = errors.New("invalid hasPrefix syntax")
+	ErrInvalidHasSuffixSyntax     = errors.New("invalid hasSuffix syntax")
+	ErrInvalidContainsSyntax      = errors.New("invalid contains syntax")
+	ErrUnknownMatcher             = errors.New("unknown matcher")
 )
 
 // Matcher defines the interface for custom value matching.
@@ -24,6 +40,33 @@ type Matcher interface {
 	String() string
 }
 
+// PathMatcher is an optional extension of Matcher for matchers that need the
+// path of the value being matched. When implemented, MatchAt is preferred over
+// Match and a non-empty reason is included in the reported difference.
+type PathMatcher interface {
+	Matcher
+	// MatchAt returns whether actual matches at path, and a failure reason if not.
+	MatchAt(path string, actual any) (bool, string)
+}
+
+// matchAt matches actual using MatchAt if m is a PathMatcher, falling back to Match.
+func matchAt(m Matcher, path string, actual any) (bool, string) {
+	if pm, ok := m.(PathMatcher); ok {
+		return pm.MatchAt(path, actual)
+	}
+
+	if m.Match(actual) {
+		return true, ""
+	}
+
+	return false, explainMismatch(m, actual)
+}
+
+// explainer is implemented by built-in matchers that can explain why they rejected a value.
+type explainer interface {
+	explain(actual any) string
+}
+
 // anyStringMatcher matches any string value.
 type anyStringMatcher struct{}
 
@@ -37,6 +80,31 @@ func (m anyStringMatcher) String() string {
 	return "{{anyString}}"
 }
 
+func (m anyStringMatcher) explain(actual any) string {
+	return expectedType("string", actual)
+}
+
+// nonEmptyStringMatcher matches strings containing at least one non-whitespace character.
+type nonEmptyStringMatcher struct{}
+
+func (m nonEmptyStringMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+
+	return ok && strings.TrimSpace(s) != ""
+}
+
+func (m nonEmptyStringMatcher) String() string {
+	return "{{nonEmptyString}}"
+}
+
+func (m nonEmptyStringMatcher) explain(actual any) string {
+	if _, ok := actual.(string); ok {
+		return "string is empty or whitespace only"
+	}
+
+	return expectedType("non-empty string", actual)
+}
+
 // anyIntMatcher matches any integer value (including float64 with no decimal part).
 type anyIntMatcher struct{}
 
@@ -50,6 +118,10 @@ func (m anyIntMatcher) Match(actual any) bool {
 		return v == float64(int64(v))
 	case float32:
 		return v == float32(int32(v))
+	case json.Number:
+		f, err := v.Float64()
+
+		return err == nil && f == float64(int64(f))
 	}
 
 	return false
@@ -59,22 +131,151 @@ func (m anyIntMatcher) String() string {
 	return "{{anyInt}}"
 }
 
+func (m anyIntMatcher) explain(actual any) string {
+	if f, ok := toFloat64(actual); ok {
+		return "expected integer, got decimal " + formatFloat(f)
+	}
+
+	return expectedType("integer", actual)
+}
+
 // anyFloatMatcher matches any numeric value.
 type anyFloatMatcher struct{}
 
 func (m anyFloatMatcher) Match(actual any) bool {
-	switch actual.(type) {
-	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return true
-	}
+	_, ok := toFloat64(actual)
 
-	return false
+	return ok
 }
 
 func (m anyFloatMatcher) String() string {
 	return "{{anyFloat}}"
 }
 
+func (m anyFloatMatcher) explain(actual any) string {
+	return expectedType("number", actual)
+}
+
+// numberInRangeMatcher matches numeric values within an inclusive range.
+type numberInRangeMatcher struct {
+	minVal float64
+	maxVal float64
+}
+
+func (m numberInRangeMatcher) Match(actual any) bool {
+	v, ok := toFloat64(actual)
+	if !ok {
+		return false
+	}
+
+	return m.minVal <= v && v <= m.maxVal
+}
+
+func (m numberInRangeMatcher) String() string {
+	return fmt.Sprintf("{{numberBetween %s %s}}", formatFloat(m.minVal), formatFloat(m.maxVal))
+}
+
+func (m numberInRangeMatcher) explain(actual any) string {
+	v, ok := toFloat64(actual)
+	if !ok {
+		return expectedType("number", actual)
+	}
+
+	return fmt.Sprintf("%s is outside range [%s, %s]", formatFloat(v), formatFloat(m.minVal), formatFloat(m.maxVal))
+}
+
+// affixMatcher matches strings that start with prefix or end with suffix.
+type affixMatcher struct {
+	affix  string
+	suffix bool
+}
+
+func (m affixMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	if m.suffix {
+		return strings.HasSuffix(s, m.affix)
+	}
+
+	return strings.HasPrefix(s, m.affix)
+}
+
+func (m affixMatcher) String() string {
+	return "{{" + m.keyword() + " " + strconv.Quote(m.affix) + "}}"
+}
+
+func (m affixMatcher) explain(actual any) string {
+	s, ok := actual.(string)
+	if !ok {
+		return expectedType("string", actual)
+	}
+
+	if m.suffix {
+		return fmt.Sprintf("%q does not end with %q", s, m.affix)
+	}
+
+	return fmt.Sprintf("%q does not start with %q", s, m.affix)
+}
+
+func (m affixMatcher) keyword() string {
+	if m.suffix {
+		return "hasSuffix"
+	}
+
+	return "hasPrefix"
+}
+
+// substringMatcher matches strings containing substr.
+type substringMatcher struct {
+	substr string
+}
+
+func (m substringMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+
+	return ok && strings.Contains(s, m.substr)
+}
+
+func (m substringMatcher) String() string {
+	return "{{contains " + strconv.Quote(m.substr) + "}}"
+}
+
+func (m substringMatcher) explain(actual any) string {
+	s, ok := actual.(string)
+	if !ok {
+		return expectedType("string", actual)
+	}
+
+	return fmt.Sprintf("%q does not contain %q", s, m.substr)
+}
+
+// arrayLenMatcher matches arrays with exactly n elements.
+type arrayLenMatcher struct {
+	n int
+}
+
+func (m arrayLenMatcher) Match(actual any) bool {
+	arr, ok := actual.([]any)
+
+	return ok && len(arr) == m.n
+}
+
+func (m arrayLenMatcher) String() string {
+	return fmt.Sprintf("{{arrayLen %d}}", m.n)
+}
+
+func (m arrayLenMatcher) explain(actual any) string {
+	arr, ok := actual.([]any)
+	if !ok {
+		return expectedType("array", actual)
+	}
+
+	return fmt.Sprintf("expected array of length %d, got length %d", m.n, len(arr))
+}
+
 // anyBoolMatcher matches any boolean value.
 type anyBoolMatcher struct{}
 
@@ -88,6 +289,39 @@ func (m anyBoolMatcher) String() string {
 	return "{{anyBool}}"
 }
 
+func (m anyBoolMatcher) explain(actual any) string {
+	return expectedType("boolean", actual)
+}
+
+// anyBoolishMatcher matches booleans and their common string representations.
+type anyBoolishMatcher struct{}
+
+func (m anyBoolishMatcher) Match(actual any) bool {
+	switch v := actual.(type) {
+	case bool:
+		return true
+	case string:
+		switch strings.ToLower(v) {
+		case "true", "false", "1", "0":
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m anyBoolishMatcher) String() string {
+	return "{{anyBoolish}}"
+}
+
+func (m anyBoolishMatcher) explain(actual any) string {
+	if _, ok := actual.(string); ok {
+		return "value is not one of true, false, 1, 0"
+	}
+
+	return expectedType("boolean or boolean string", actual)
+}
+
 // anyValueMatcher matches any value including null.
 type anyValueMatcher struct{}
 
@@ -99,6 +333,59 @@ func (m anyValueMatcher) String() string {
 	return "{{anyValue}}"
 }
 
+// presentMatcher matches any value except null.
+type presentMatcher struct{}
+
+func (m presentMatcher) Match(actual any) bool {
+	return actual != nil
+}
+
+func (m presentMatcher) String() string {
+	return "{{present}}"
+}
+
+func (m presentMatcher) explain(_ any) string {
+	return "expected a non-null value, got null"
+}
+
+// emptyMatcher matches an empty string, array, or object.
+type emptyMatcher struct{}
+
+func (m emptyMatcher) Match(actual any) bool {
+	switch v := actual.(type) {
+	case nil:
+		return false
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+
+	rv := reflect.ValueOf(actual)
+	//nolint:exhaustive // Only collection types can be empty.
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	default:
+		return false
+	}
+}
+
+func (m emptyMatcher) String() string {
+	return "{{empty}}"
+}
+
+func (m emptyMatcher) explain(actual any) string {
+	switch actual.(type) {
+	case string, []any, map[string]any:
+		return jsonTypeName(actual) + " is not empty"
+	default:
+		return expectedType("empty string, array or object", actual)
+	}
+}
+
 // ignoreMatcher indicates a field should be skipped during comparison.
 type ignoreMatcher struct{}
 
@@ -119,8 +406,9 @@ func IsIgnore(m Matcher) bool {
 
 // regexMatcher matches string values against a regular expression.
 type regexMatcher struct {
-	pattern string
-	re      *regexp.Regexp
+	pattern         string
+	caseInsensitive bool
+	re              *regexp.Regexp
 }
 
 func (m *regexMatcher) Match(actual any) bool {
@@ -133,9 +421,25 @@ func (m *regexMatcher) Match(actual any) bool {
 }
 
 func (m *regexMatcher) String() string {
+	if m.caseInsensitive {
+		return fmt.Sprintf("{{regexi `%s`}}", m.pattern)
+	}
+
 	return fmt.Sprintf("{{regex `%s`}}", m.pattern)
 }
 
+func (m *regexMatcher) explain(actual any) string {
+	if _, ok := actual.(string); !ok {
+		return expectedType("string", actual)
+	}
+
+	if m.caseInsensitive {
+		return fmt.Sprintf("value does not match pattern `%s` (case-insensitive)", m.pattern)
+	}
+
+	return fmt.Sprintf("value does not match pattern `%s`", m.pattern)
+}
+
 // oneOfMatcher matches if the value equals one of the allowed values.
 type oneOfMatcher struct {
 	values []any
@@ -146,7 +450,264 @@ func (m *oneOfMatcher) Match(actual any) bool {
 }
 
 func (m *oneOfMatcher) String() string {
-	return fmt.Sprintf("{{oneOf %v}}", m.values)
+	return "{{oneOf " + strings.Join(m.quotedValues(), " ") + "}}"
+}
+
+func (m *oneOfMatcher) explain(actual any) string {
+	return fmt.Sprintf("%s is not in allowed set {%s}",
+		formatAllowedValue(actual), strings.Join(m.quotedValues(), ", "))
+}
+
+// quotedValues formats the allowed values, quoting strings.
+func (m *oneOfMatcher) quotedValues() []string {
+	parts := make([]string, 0, len(m.values))
+
+	for _, v := range m.values {
+		parts = append(parts, formatAllowedValue(v))
+	}
+
+	return parts
+}
+
+// formatAllowedValue formats a value for oneOf output, quoting strings.
+func formatAllowedValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// jsonTypes are the type names accepted by typeOneOf, as classified by typeOf.
+//
+//nolint:gochecknoglobals // Fixed set of JSON type names.
+var jsonTypes = []string{"string", "number", "boolean", "object", "array", "null"}
+
+// typeOneOfMatcher matches if the JSON type of the value is one of the allowed types.
+type typeOneOfMatcher struct {
+	types []string
+}
+
+func (m *typeOneOfMatcher) Match(actual any) bool {
+	return slices.Contains(m.types, typeOf(actual))
+}
+
+func (m *typeOneOfMatcher) String() string {
+	parts := make([]string, 0, len(m.types))
+	for _, t := range m.types {
+		parts = append(parts, strconv.Quote(t))
+	}
+
+	return "{{typeOneOf " + strings.Join(parts, " ") + "}}"
+}
+
+func (m *typeOneOfMatcher) explain(actual any) string {
+	return fmt.Sprintf("expected %s, got %s", strings.Join(m.types, " or "), typeOf(actual))
+}
+
+// notMatcher negates the result of the wrapped matcher.
+type notMatcher struct {
+	matcher Matcher
+}
+
+func (m *notMatcher) Match(actual any) bool {
+	return !m.matcher.Match(actual)
+}
+
+func (m *notMatcher) String() string {
+	return "{{not " + matcherExpr(m.matcher) + "}}"
+}
+
+func (m *notMatcher) explain(actual any) string {
+	return "value matches " + m.matcher.String() + ", which is not allowed"
+}
+
+// allOfMatcher matches if all of the wrapped matchers match.
+type allOfMatcher struct {
+	matchers []Matcher
+}
+
+func (m *allOfMatcher) Match(actual any) bool {
+	return m.firstFailure(actual) == nil
+}
+
+func (m *allOfMatcher) String() string {
+	parts := make([]string, 0, len(m.matchers))
+
+	for _, sub := range m.matchers {
+		parts = append(parts, "("+matcherExpr(sub)+")")
+	}
+
+	return "{{allOf " + strings.Join(parts, " ") + "}}"
+}
+
+// firstFailure returns the first wrapped matcher that does not match, or nil if all match.
+func (m *allOfMatcher) firstFailure(actual any) Matcher {
+	for _, sub := range m.matchers {
+		if !sub.Match(actual) {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// hasClassMatcher matches a whitespace-separated class list containing all required classes.
+type hasClassMatcher struct {
+	classes []string
+}
+
+func (m hasClassMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	present := strings.Fields(s)
+	for _, class := range m.classes {
+		if !slices.Contains(present, class) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m hasClassMatcher) String() string {
+	parts := make([]string, 0, len(m.classes))
+	for _, class := range m.classes {
+		parts = append(parts, strconv.Quote(class))
+	}
+
+	return "{{hasClass " + strings.Join(parts, " ") + "}}"
+}
+
+func (m hasClassMatcher) explain(actual any) string {
+	s, ok := actual.(string)
+	if !ok {
+		return expectedType("string", actual)
+	}
+
+	present := strings.Fields(s)
+
+	var missing []string
+
+	for _, class := range m.classes {
+		if !slices.Contains(present, class) {
+			missing = append(missing, strconv.Quote(class))
+		}
+	}
+
+	return "missing classes " + strings.Join(missing, ", ")
+}
+
+// anyTimestampMatcher matches strings that parse as a timestamp in the given layout.
+type anyTimestampMatcher struct {
+	layout string
+}
+
+func (m anyTimestampMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	_, err := time.Parse(m.layout, s)
+
+	return err == nil
+}
+
+func (m anyTimestampMatcher) String() string {
+	if m.layout == time.RFC3339 {
+		return "{{anyTimestamp}}"
+	}
+
+	return fmt.Sprintf("{{anyTimestamp %q}}", m.layout)
+}
+
+func (m anyTimestampMatcher) explain(actual any) string {
+	if _, ok := actual.(string); !ok {
+		return expectedType("string", actual)
+	}
+
+	return fmt.Sprintf("value is not a timestamp in layout %q", m.layout)
+}
+
+// isoDurationRegex matches ISO-8601 durations such as P3Y6M4DT12H30M5S, PT1H30M or P2W.
+// Every component is optional, so isISODuration also requires at least one of them.
+var isoDurationRegex = regexp.MustCompile(
+	`^[-+]?P(?:\d+(?:[.,]\d+)?Y)?(?:\d+(?:[.,]\d+)?M)?(?:\d+(?:[.,]\d+)?W)?(?:\d+(?:[.,]\d+)?D)?` +
+		`(?:T(?:\d+(?:[.,]\d+)?H)?(?:\d+(?:[.,]\d+)?M)?(?:\d+(?:[.,]\d+)?S)?)?$`,
+)
+
+// isISODuration reports whether s is an ISO-8601 duration with at least one component.
+func isISODuration(s string) bool {
+	if !isoDurationRegex.MatchString(s) || strings.HasSuffix(s, "T") {
+		return false
+	}
+
+	return strings.ContainsAny(s, "YMWDHS")
+}
+
+// durationMatcher matches strings that parse as an ISO-8601 or a Go duration.
+type durationMatcher struct {
+	goSyntax bool
+}
+
+func (m durationMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	if m.goSyntax {
+		_, err := time.ParseDuration(s)
+
+		return err == nil
+	}
+
+	return isISODuration(s)
+}
+
+func (m durationMatcher) String() string {
+	if m.goSyntax {
+		return "{{anyGoDuration}}"
+	}
+
+	return "{{anyDuration}}"
+}
+
+func (m durationMatcher) explain(actual any) string {
+	if _, ok := actual.(string); !ok {
+		return expectedType("string", actual)
+	}
+
+	if m.goSyntax {
+		return "value is not a Go duration like 1h30m"
+	}
+
+	return "value is not an ISO-8601 duration like PT1H30M"
+}
+
+// anyJSONMatcher matches strings that are themselves valid JSON, e.g. double-encoded payloads.
+type anyJSONMatcher struct{}
+
+func (m anyJSONMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+
+	return ok && json.Valid([]byte(s))
+}
+
+func (m anyJSONMatcher) String() string {
+	return "{{anyJSON}}"
+}
+
+func (m anyJSONMatcher) explain(actual any) string {
+	if _, ok := actual.(string); !ok {
+		return expectedType("string", actual)
+	}
+
+	return "string is not valid JSON"
 }
 
 // Template function constructors for creating matchers.
@@ -157,6 +718,12 @@ func AnyString() Matcher {
 	return anyStringMatcher{}
 }
 
+// NonEmptyString returns a matcher that matches strings with at least one
+// non-whitespace character. Unlike AnyString, it rejects "" and "  ".
+func NonEmptyString() Matcher {
+	return nonEmptyStringMatcher{}
+}
+
 // AnyInt returns a matcher that matches any integer value.
 func AnyInt() Matcher {
 	return anyIntMatcher{}
@@ -167,21 +734,102 @@ func AnyFloat() Matcher {
 	return anyFloatMatcher{}
 }
 
+// NumberInRange returns a matcher that matches any numeric value v with minVal <= v <= maxVal.
+func NumberInRange(minVal, maxVal float64) Matcher {
+	return numberInRangeMatcher{minVal: minVal, maxVal: maxVal}
+}
+
 // AnyBool returns a matcher that matches any boolean value.
 func AnyBool() Matcher {
 	return anyBoolMatcher{}
 }
 
+// AnyBoolish returns a matcher that matches booleans and the strings "true", "false",
+// "1" and "0" (case-insensitive), e.g. for HTML attributes where every value is a string.
+func AnyBoolish() Matcher {
+	return anyBoolishMatcher{}
+}
+
 // AnyValue returns a matcher that matches any value including null.
 func AnyValue() Matcher {
 	return anyValueMatcher{}
 }
 
+// StartsWith returns a matcher that matches strings starting with prefix, written
+// {{hasPrefix "https://"}} in expected files.
+func StartsWith(prefix string) Matcher {
+	return affixMatcher{affix: prefix}
+}
+
+// EndsWith returns a matcher that matches strings ending with suffix, written
+// {{hasSuffix ".png"}} in expected files.
+func EndsWith(suffix string) Matcher {
+	return affixMatcher{affix: suffix, suffix: true}
+}
+
+// Substring returns a matcher that matches strings containing substr, written
+// {{contains "error"}} in expected files, e.g. for text that is only partly fixed.
+func Substring(substr string) Matcher {
+	return substringMatcher{substr: substr}
+}
+
+// ArrayLen returns a matcher that matches arrays with exactly n elements, regardless
+// of their contents. Use it in place of the array in an expected file.
+func ArrayLen(n int) Matcher {
+	return arrayLenMatcher{n: n}
+}
+
+// Present returns a matcher that matches any value except null. JSON null decodes
+// to nil and fails, and a missing key is reported as removed like for any expected
+// field, so {{present}} asserts that a field exists with a non-null value.
+func Present() Matcher {
+	return presentMatcher{}
+}
+
+// EmptyValue returns a matcher that matches an empty string, array, or object.
+// It does not match null, so the field must be present.
+func EmptyValue() Matcher {
+	return emptyMatcher{}
+}
+
 // Ignore returns a matcher that causes the field to be skipped.
 func Ignore() Matcher {
 	return ignoreMatcher{}
 }
 
+// AnyTimestamp returns a matcher that matches RFC3339 (ISO 8601) timestamp strings.
+func AnyTimestamp() Matcher {
+	return anyTimestampMatcher{layout: time.RFC3339}
+}
+
+// AnyTimestampLayout returns a matcher that matches timestamp strings in the given
+// time.Parse layout, e.g. "2006-01-02" for date-only values.
+func AnyTimestampLayout(layout string) Matcher {
+	return anyTimestampMatcher{layout: layout}
+}
+
+// AnyDuration returns a matcher that matches ISO-8601 duration strings, e.g. "PT1H30M" or "P1DT12H".
+func AnyDuration() Matcher {
+	return durationMatcher{}
+}
+
+// AnyGoDuration returns a matcher that matches strings accepted by time.ParseDuration, e.g. "1h30m".
+func AnyGoDuration() Matcher {
+	return durationMatcher{goSyntax: true}
+}
+
+// AnyJSON returns a matcher that matches strings containing valid JSON, e.g. a payload
+// embedded as a JSON-encoded string, without checking the embedded content.
+func AnyJSON() Matcher {
+	return anyJSONMatcher{}
+}
+
+// HasClass returns a matcher for class attribute values that contain all of the
+// given classes, ignoring other classes and their order.
+func HasClass(classes ...string) Matcher {
+	return hasClassMatcher{classes: classes}
+}
+
 // Regex returns a matcher that matches strings against a regex pattern.
 func Regex(pattern string) (Matcher, error) {
 	re, err := regexp.Compile(pattern)
@@ -192,27 +840,213 @@ func Regex(pattern string) (Matcher, error) {
 	return &regexMatcher{pattern: pattern, re: re}, nil
 }
 
+// RegexI returns a matcher that matches strings against the pattern case-insensitively,
+// like Regex with a (?i) prefix.
+func RegexI(pattern string) (Matcher, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern %q: %w", pattern, err)
+	}
+
+	return &regexMatcher{pattern: pattern, caseInsensitive: true, re: re}, nil
+}
+
 // OneOf returns a matcher that matches if the value equals one of the given values.
 func OneOf(values ...any) Matcher {
 	return &oneOfMatcher{values: values}
 }
 
+// TypeOneOf returns a matcher that matches if the JSON type of the value is one of
+// the given types: "string", "number", "boolean", "object", "array" or "null".
+// Use it for polymorphic fields, e.g. TypeOneOf("string", "number").
+func TypeOneOf(types ...string) Matcher {
+	return &typeOneOfMatcher{types: types}
+}
+
+// Not returns a matcher that matches if the given matcher does not match.
+func Not(m Matcher) Matcher {
+	return &notMatcher{matcher: m}
+}
+
+// AllOf returns a matcher that matches if all of the given matchers match.
+func AllOf(matchers ...Matcher) Matcher {
+	return &allOfMatcher{matchers: matchers}
+}
+
 // ParseMatcher creates a Matcher from a template expression.
 // The expression is the content between {{ and }}.
 func ParseMatcher(expr string) (Matcher, error) {
 	switch expr {
 	case "anyString":
 		return AnyString(), nil
+	case "nonEmptyString":
+		return NonEmptyString(), nil
 	case "anyInt":
 		return AnyInt(), nil
 	case "anyFloat":
 		return AnyFloat(), nil
 	case "anyBool":
 		return AnyBool(), nil
+	case "anyBoolish":
+		return AnyBoolish(), nil
 	case "anyValue":
 		return AnyValue(), nil
+	case "present":
+		return Present(), nil
 	case "ignore":
 		return Ignore(), nil
+	case "empty":
+		return EmptyValue(), nil
+	case "anyTimestamp":
+		return AnyTimestamp(), nil
+	case "anySemver":
+		return AnySemver(), nil
+	case "anyDuration":
+		return AnyDuration(), nil
+	case "anyGoDuration":
+		return AnyGoDuration(), nil
+	case "anyJSON":
+		return AnyJSON(), nil
+	}
+
+	// Handle capture "name" and ref "name"
+	if rest, ok := strings.CutPrefix(expr, "capture "); ok {
+		name, ok := singleQuotedArg(rest)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCaptureSyntax, expr)
+		}
+
+		return Capture(name), nil
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "ref "); ok {
+		name, ok := singleQuotedArg(rest)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRefSyntax, expr)
+		}
+
+		return Ref(name), nil
+	}
+
+	// Handle type <name>
+	if rest, ok := strings.CutPrefix(expr, "type "); ok {
+		m, ok := typeMatcher(trimSpace(rest))
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTypeSyntax, expr)
+		}
+
+		return m, nil
+	}
+
+	// Handle not <expr>
+	if rest, ok := strings.CutPrefix(expr, "not "); ok {
+		inner, err := ParseMatcher(trimSpace(rest))
+		if err != nil {
+			return nil, err
+		}
+
+		if IsIgnore(inner) {
+			return nil, fmt.Errorf("%w: cannot negate ignore", ErrInvalidNotSyntax)
+		}
+
+		return Not(inner), nil
+	}
+
+	// Handle allOf (expr) (expr) ...
+	if rest, ok := strings.CutPrefix(expr, "allOf "); ok {
+		args, err := extractParenArgs(rest)
+		if err != nil || len(args) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAllOfSyntax, expr)
+		}
+
+		matchers := make([]Matcher, 0, len(args))
+
+		for _, arg := range args {
+			sub, err := ParseMatcher(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			matchers = append(matchers, sub)
+		}
+
+		return AllOf(matchers...), nil
+	}
+
+	// Handle hasPrefix "p" and hasSuffix "s"
+	if rest, ok := strings.CutPrefix(expr, "hasPrefix "); ok {
+		prefix, ok := singleQuotedArg(rest)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHasPrefixSyntax, expr)
+		}
+
+		return StartsWith(prefix), nil
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "hasSuffix "); ok {
+		suffix, ok := singleQuotedArg(rest)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHasSuffixSyntax, expr)
+		}
+
+		return EndsWith(suffix), nil
+	}
+
+	// Handle contains "s"
+	if rest, ok := strings.CutPrefix(expr, "contains "); ok {
+		substr, ok := singleQuotedArg(rest)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidContainsSyntax, expr)
+		}
+
+		return Substring(substr), nil
+	}
+
+	// Handle arrayLen n
+	if rest, ok := strings.CutPrefix(expr, "arrayLen "); ok {
+		n, err := strconv.Atoi(trimSpace(rest))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArrayLenSyntax, expr)
+		}
+
+		return ArrayLen(n), nil
+	}
+
+	// Handle numberBetween min max
+	if rest, ok := strings.CutPrefix(expr, "numberBetween "); ok {
+		bounds := strings.Fields(rest)
+		if len(bounds) != 2 { //nolint:mnd // Lower and upper bound.
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNumberBetweenSyntax, expr)
+		}
+
+		minVal, minErr := strconv.ParseFloat(bounds[0], 64)
+		maxVal, maxErr := strconv.ParseFloat(bounds[1], 64)
+
+		if minErr != nil || maxErr != nil || minVal > maxVal {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNumberBetweenSyntax, expr)
+		}
+
+		return NumberInRange(minVal, maxVal), nil
+	}
+
+	// Handle anyTimestamp "layout"
+	if rest, ok := strings.CutPrefix(expr, "anyTimestamp "); ok {
+		layout := extractQuotedArg(rest)
+		if layout != "" {
+			return AnyTimestampLayout(layout), nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimestampSyntax, expr)
+	}
+
+	// Handle semverRange "constraint"
+	if rest, ok := strings.CutPrefix(expr, "semverRange "); ok {
+		constraint := extractQuotedArg(rest)
+		if constraint == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSemverRangeSyntax, expr)
+		}
+
+		return SemverRange(constraint)
 	}
 
 	// Handle regex `pattern`
@@ -230,6 +1064,20 @@ func ParseMatcher(expr string) (Matcher, error) {
 		return nil, fmt.Errorf("%w: %s", ErrInvalidRegexSyntax, expr)
 	}
 
+	// Handle regexi `pattern`
+	if rest, ok := strings.CutPrefix(expr, "regexi "); ok {
+		pattern := extractBacktickArg(rest)
+		if pattern == "" {
+			pattern = extractQuotedArg(rest)
+		}
+
+		if pattern != "" {
+			return RegexI(pattern)
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRegexSyntax, expr)
+	}
+
 	// Handle oneOf "a" "b" "c"
 	if len(expr) > 6 && expr[:6] == "oneOf " {
 		values := extractQuotedArgs(expr[6:])
@@ -240,9 +1088,164 @@ func ParseMatcher(expr string) (Matcher, error) {
 		return nil, fmt.Errorf("%w: %s", ErrInvalidOneOfSyntax, expr)
 	}
 
+	// Handle typeOneOf "string" "number"
+	if rest, ok := strings.CutPrefix(expr, "typeOneOf "); ok {
+		values := extractQuotedArgs(rest)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTypeOneOfSyntax, expr)
+		}
+
+		types := make([]string, 0, len(values))
+		for _, v := range values {
+			typ, _ := v.(string)
+			if !slices.Contains(jsonTypes, typ) {
+				return nil, fmt.Errorf("%w: unknown type %q in %s", ErrInvalidTypeOneOfSyntax, typ, expr)
+			}
+
+			types = append(types, typ)
+		}
+
+		return TypeOneOf(types...), nil
+	}
+
+	// Handle hasClass "a" "b"
+	if rest, ok := strings.CutPrefix(expr, "hasClass "); ok {
+		values := extractQuotedArgs(rest)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHasClassSyntax, expr)
+		}
+
+		classes := make([]string, 0, len(values))
+		for _, v := range values {
+			class, _ := v.(string)
+			if class == "" || strings.ContainsAny(class, " \t\n") {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidHasClassSyntax, expr)
+			}
+
+			classes = append(classes, class)
+		}
+
+		return HasClass(classes...), nil
+	}
+
+	// Handle matchers registered with RegisterMatcher
+	registered, ok, err := lookupRegisteredMatcher(expr)
+	if ok {
+		return registered, err
+	}
+
 	return nil, fmt.Errorf("%w: %s", ErrUnknownMatcher, expr)
 }
 
+// toFloat64 converts a numeric value to float64.
+// Returns false if the value is not numeric.
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// formatFloat formats a float for template expressions without a trailing decimal part.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// failureFinder is implemented by composite matchers that can name the part that failed.
+type failureFinder interface {
+	firstFailure(actual any) Matcher
+}
+
+// describeMismatch returns the expectation to report when m does not match actual.
+// For combinators and builders, it names the sub-matcher that rejected the value.
+func describeMismatch(m Matcher, actual any) string {
+	if f, ok := m.(failureFinder); ok {
+		if failed := f.firstFailure(actual); failed != nil {
+			return fmt.Sprintf("%s (failed: %s)", m.String(), failed.String())
+		}
+	}
+
+	return m.String()
+}
+
+// explainMismatch returns why m rejected actual, or "" if m cannot tell. Composite
+// matchers explain the failure of the sub-matcher that rejected the value.
+func explainMismatch(m Matcher, actual any) string {
+	if e, ok := m.(explainer); ok {
+		return e.explain(actual)
+	}
+
+	if f, ok := m.(failureFinder); ok {
+		if failed := f.firstFailure(actual); failed != nil {
+			return explainMismatch(failed, actual)
+		}
+	}
+
+	return ""
+}
+
+// expectedType explains a type mismatch, e.g. "expected string, got number".
+func expectedType(want string, actual any) string {
+	return "expected " + want + ", got " + jsonTypeName(actual)
+}
+
+// jsonTypeName returns the JSON type name of a decoded value.
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	if _, ok := toFloat64(v); ok {
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// matcherExpr returns the template expression of a matcher without the surrounding braces.
+func matcherExpr(m Matcher) string {
+	s := strings.TrimPrefix(m.String(), "{{")
+
+	return strings.TrimSuffix(s, "}}")
+}
+
 // extractBacktickArg extracts content from backticks.
 func extractBacktickArg(s string) string {
 	s = trimSpace(s)
@@ -257,8 +1260,9 @@ func extractBacktickArg(s string) string {
 }
 
 // extractQuotedArg extracts content from quotes.
+// Handles both regular quotes and JSON-escaped quotes (\" or \\").
 func extractQuotedArg(s string) string {
-	s = trimSpace(s)
+	s = unescapeQuotes(trimSpace(s))
 	if len(s) >= 2 && s[0] == '"' {
 		end := indexOf(s[1:], '"')
 		if end >= 0 {
@@ -274,19 +1278,43 @@ func extractQuotedArg(s string) string {
 	return ""
 }
 
+// singleQuotedArg returns the only argument of s if it is a non-empty quoted string.
+func singleQuotedArg(s string) (string, bool) {
+	args := extractQuotedArgs(s)
+	if len(args) != 1 {
+		return "", false
+	}
+
+	name, _ := args[0].(string)
+
+	return name, name != ""
+}
+
+// typeMatcher returns the matcher for a {{type <name>}} expression. The scalar types
+// map to the corresponding anyX matchers.
+func typeMatcher(name string) (Matcher, bool) {
+	switch name {
+	case "string":
+		return AnyString(), true
+	case "int":
+		return AnyInt(), true
+	case "float":
+		return AnyFloat(), true
+	case "bool":
+		return AnyBool(), true
+	case "object", "array", "null":
+		return TypeOneOf(name), true
+	default:
+		return nil, false
+	}
+}
+
 // extractQuotedArgs extracts multiple quoted strings.
 // Handles both regular quotes and JSON-escaped quotes (\" or \\").
 func extractQuotedArgs(s string) []any {
 	var result []any
 
-	s = trimSpace(s)
-
-	// Handle JSON-escaped quotes: \" or \\"
-	if strings.Contains(s, `\"`) || strings.Contains(s, `\\"`) {
-		// Replace escaped quotes with regular quotes
-		s = strings.ReplaceAll(s, `\\"`, `"`)
-		s = strings.ReplaceAll(s, `\"`, `"`)
-	}
+	s = unescapeQuotes(trimSpace(s))
 
 	for len(s) > 0 && s[0] == '"' {
 		end := indexOf(s[1:], '"')
@@ -307,6 +1335,87 @@ func extractQuotedArgs(s string) []any {
 	return result
 }
 
+// extractParenArgs extracts parenthesized sub-expressions, e.g. "(a) (b c)" -> ["a", "b c"].
+// Parentheses inside backticks or quotes are not treated as delimiters.
+func extractParenArgs(s string) ([]string, error) {
+	var result []string
+
+	s = unescapeQuotes(trimSpace(s))
+
+	for len(s) > 0 {
+		if s[0] != '(' {
+			return nil, fmt.Errorf("%w: expected '(' in %q", ErrInvalidAllOfSyntax, s)
+		}
+
+		end := findClosingParen(s)
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unbalanced parentheses in %q", ErrInvalidAllOfSyntax, s)
+		}
+
+		result = append(result, trimSpace(s[1:end]))
+		s = trimSpace(s[end+1:])
+	}
+
+	return result, nil
+}
+
+// findClosingParen returns the index of the parenthesis closing the one at s[0], or -1.
+func findClosingParen(s string) int {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '`':
+			end := indexOf(s[i+1:], '`')
+			if end < 0 {
+				return -1
+			}
+
+			i += end + 1
+		case '"':
+			i = skipQuoted(s, i)
+			if i < 0 {
+				return -1
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// skipQuoted returns the index of the quote closing the one at s[start], or -1.
+// Backslash-escaped quotes are skipped.
+func skipQuoted(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unescapeQuotes replaces JSON-escaped quotes (\" or \\") with regular quotes,
+// as found in template expressions embedded in JSON strings.
+func unescapeQuotes(s string) string {
+	if strings.Contains(s, `\"`) || strings.Contains(s, `\\"`) {
+		s = strings.ReplaceAll(s, `\\"`, `"`)
+		s = strings.ReplaceAll(s, `\"`, `"`)
+	}
+
+	return s
+}
+
 func trimSpace(s string) string {
 	start := 0
 
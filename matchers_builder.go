@@ -0,0 +1,171 @@
+package testastic
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// constraintMatcher labels a matcher with the short description used in builder chains.
+type constraintMatcher struct {
+	Matcher
+
+	desc string
+}
+
+func (m constraintMatcher) String() string {
+	return m.desc
+}
+
+func (m constraintMatcher) explain(actual any) string {
+	if reason := explainMismatch(m.Matcher, actual); reason != "" {
+		return reason
+	}
+
+	return "value does not satisfy " + m.desc
+}
+
+// predicateMatcher matches values accepted by a predicate function.
+type predicateMatcher func(actual any) bool
+
+func (m predicateMatcher) Match(actual any) bool {
+	return m(actual)
+}
+
+func (m predicateMatcher) String() string {
+	return "predicate"
+}
+
+// StringMatcher is a chainable Matcher for string values, e.g.
+// testastic.String().NonEmpty().MaxLen(64). Each method returns a new matcher.
+type StringMatcher struct {
+	constraints []Matcher
+}
+
+// String returns a matcher for any string value that can be narrowed with chained constraints.
+func String() *StringMatcher {
+	return &StringMatcher{}
+}
+
+// NonEmpty requires the string to be non-empty.
+func (m *StringMatcher) NonEmpty() *StringMatcher {
+	return m.with(Not(EmptyValue()), "nonEmpty")
+}
+
+// MinLen requires the string to have at least n characters.
+func (m *StringMatcher) MinLen(n int) *StringMatcher {
+	return m.with(predicateMatcher(func(actual any) bool {
+		s, _ := actual.(string)
+
+		return utf8.RuneCountInString(s) >= n
+	}), "minLen "+strconv.Itoa(n))
+}
+
+// MaxLen requires the string to have at most n characters.
+func (m *StringMatcher) MaxLen(n int) *StringMatcher {
+	return m.with(predicateMatcher(func(actual any) bool {
+		s, _ := actual.(string)
+
+		return utf8.RuneCountInString(s) <= n
+	}), "maxLen "+strconv.Itoa(n))
+}
+
+// Match returns true if actual is a string satisfying all constraints.
+func (m *StringMatcher) Match(actual any) bool {
+	return m.firstFailure(actual) == nil
+}
+
+// String describes the full constraint chain, e.g. {{string nonEmpty maxLen 64}}.
+func (m *StringMatcher) String() string {
+	return describeChain("string", m.constraints)
+}
+
+func (m *StringMatcher) with(c Matcher, desc string) *StringMatcher {
+	return &StringMatcher{
+		constraints: append(slices.Clone(m.constraints), constraintMatcher{Matcher: c, desc: desc}),
+	}
+}
+
+func (m *StringMatcher) firstFailure(actual any) Matcher {
+	if !AnyString().Match(actual) {
+		return constraintMatcher{Matcher: AnyString(), desc: "string"}
+	}
+
+	return (&allOfMatcher{matchers: m.constraints}).firstFailure(actual)
+}
+
+// NumberMatcher is a chainable Matcher for numeric values, e.g.
+// testastic.Number().Integer().Between(0, 100). Each method returns a new matcher.
+type NumberMatcher struct {
+	constraints []Matcher
+}
+
+// Number returns a matcher for any numeric value that can be narrowed with chained constraints.
+func Number() *NumberMatcher {
+	return &NumberMatcher{}
+}
+
+// Integer requires the number to be an integer.
+func (m *NumberMatcher) Integer() *NumberMatcher {
+	return m.with(AnyInt(), "integer")
+}
+
+// Between requires minVal <= number <= maxVal.
+func (m *NumberMatcher) Between(minVal, maxVal float64) *NumberMatcher {
+	return m.with(NumberInRange(minVal, maxVal), "between "+formatFloat(minVal)+" "+formatFloat(maxVal))
+}
+
+// Min requires number >= minVal.
+func (m *NumberMatcher) Min(minVal float64) *NumberMatcher {
+	return m.with(predicateMatcher(func(actual any) bool {
+		f, _ := toFloat64(actual)
+
+		return f >= minVal
+	}), "min "+formatFloat(minVal))
+}
+
+// Max requires number <= maxVal.
+func (m *NumberMatcher) Max(maxVal float64) *NumberMatcher {
+	return m.with(predicateMatcher(func(actual any) bool {
+		f, _ := toFloat64(actual)
+
+		return f <= maxVal
+	}), "max "+formatFloat(maxVal))
+}
+
+// Match returns true if actual is a number satisfying all constraints.
+func (m *NumberMatcher) Match(actual any) bool {
+	return m.firstFailure(actual) == nil
+}
+
+// String describes the full constraint chain, e.g. {{number integer between 0 100}}.
+func (m *NumberMatcher) String() string {
+	return describeChain("number", m.constraints)
+}
+
+func (m *NumberMatcher) with(c Matcher, desc string) *NumberMatcher {
+	return &NumberMatcher{
+		constraints: append(slices.Clone(m.constraints), constraintMatcher{Matcher: c, desc: desc}),
+	}
+}
+
+func (m *NumberMatcher) firstFailure(actual any) Matcher {
+	if !AnyFloat().Match(actual) {
+		return constraintMatcher{Matcher: AnyFloat(), desc: "number"}
+	}
+
+	return (&allOfMatcher{matchers: m.constraints}).firstFailure(actual)
+}
+
+// describeChain renders a builder as a template-like expression listing its constraints.
+func describeChain(base string, constraints []Matcher) string {
+	parts := make([]string, 0, len(constraints)+1)
+	parts = append(parts, base)
+
+	for _, c := range constraints {
+		parts = append(parts, c.String())
+	}
+
+	return "{{" + strings.Join(parts, " ") + "}}"
+}
@@ -0,0 +1,120 @@
+package testastic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidMatcherArgs is returned when the arguments of a registered matcher cannot be parsed.
+var ErrInvalidMatcherArgs = errors.New("invalid matcher arguments")
+
+// MatcherFactory creates a Matcher from the arguments of a template expression.
+type MatcherFactory func(args []string) (Matcher, error)
+
+// matcherRegistry holds user-registered matchers by name.
+//
+//nolint:gochecknoglobals // Package-level registry is the intended extension point.
+var matcherRegistry = struct {
+	sync.RWMutex
+
+	factories map[string]MatcherFactory
+}{factories: make(map[string]MatcherFactory)}
+
+// RegisterMatcher registers a custom matcher usable in expected files as {{name args...}}.
+// Arguments are passed to the factory as strings; quoted and backticked arguments are unquoted.
+// Built-in matchers take precedence over registered ones with the same name.
+//
+// Example:
+//
+//	testastic.RegisterMatcher("myToken", func(args []string) (testastic.Matcher, error) {
+//		return newTokenMatcher(args[0]), nil
+//	})
+//
+// makes {{myToken "v2"}} call the factory with ["v2"].
+func RegisterMatcher(name string, factory MatcherFactory) {
+	matcherRegistry.Lock()
+	defer matcherRegistry.Unlock()
+
+	matcherRegistry.factories[name] = factory
+}
+
+// ResetMatchers removes all registered custom matchers, e.g. for test isolation.
+func ResetMatchers() {
+	matcherRegistry.Lock()
+	defer matcherRegistry.Unlock()
+
+	matcherRegistry.factories = make(map[string]MatcherFactory)
+}
+
+// lookupRegisteredMatcher creates a matcher from the registry.
+// Returns false if no matcher is registered under the expression's name.
+func lookupRegisteredMatcher(expr string) (Matcher, bool, error) {
+	name, rest, _ := strings.Cut(expr, " ")
+
+	matcherRegistry.RLock()
+	factory, ok := matcherRegistry.factories[name]
+	matcherRegistry.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	args, err := parseMatcherArgs(rest)
+	if err != nil {
+		return nil, true, err
+	}
+
+	m, err := factory(args)
+	if err != nil {
+		return nil, true, fmt.Errorf("matcher %s: %w", name, err)
+	}
+
+	return m, true, nil
+}
+
+// parseMatcherArgs splits matcher arguments on whitespace.
+// Quoted and backticked arguments may contain spaces and are unquoted.
+func parseMatcherArgs(s string) ([]string, error) {
+	var args []string
+
+	s = unescapeQuotes(trimSpace(s))
+
+	for len(s) > 0 {
+		switch s[0] {
+		case '"':
+			end := skipQuoted(s, 0)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalidMatcherArgs, s)
+			}
+
+			arg, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				arg = s[1:end]
+			}
+
+			args = append(args, arg)
+			s = s[end+1:]
+
+		case '`':
+			end := strings.IndexByte(s[1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated backtick in %q", ErrInvalidMatcherArgs, s)
+			}
+
+			args = append(args, s[1:end+1])
+			s = s[end+2:]
+
+		default:
+			word, rest, _ := strings.Cut(s, " ")
+			args = append(args, word)
+			s = rest
+		}
+
+		s = trimSpace(s)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,336 @@
+package testastic
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSemverRangeSyntax is returned when a semverRange constraint is malformed.
+var ErrInvalidSemverRangeSyntax = errors.New("invalid semverRange syntax")
+
+// semver is a parsed semantic version (https://semver.org). Build metadata is
+// ignored for precedence and therefore not kept.
+type semver struct {
+	major, minor, patch uint64
+	prerelease          []string
+}
+
+// parseSemver parses a strict semantic version like 1.2.3, 1.2.3-rc.1 or 1.2.3+build.5.
+func parseSemver(s string) (semver, bool) {
+	var v semver
+
+	s, build, hasBuild := strings.Cut(s, "+")
+	if hasBuild && !validIdentifiers(build, false) {
+		return v, false
+	}
+
+	core, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		if !validIdentifiers(pre, true) {
+			return v, false
+		}
+
+		v.prerelease = strings.Split(pre, ".")
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 { //nolint:mnd // Major, minor and patch.
+		return v, false
+	}
+
+	nums := make([]uint64, len(parts))
+
+	for i, p := range parts {
+		n, ok := parseNumericIdentifier(p)
+		if !ok {
+			return v, false
+		}
+
+		nums[i] = n
+	}
+
+	v.major, v.minor, v.patch = nums[0], nums[1], nums[2]
+
+	return v, true
+}
+
+// parseNumericIdentifier parses a non-negative integer without leading zeros.
+func parseNumericIdentifier(s string) (uint64, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+
+	return n, err == nil
+}
+
+// validIdentifiers checks dot-separated prerelease or build identifiers.
+// Numeric prerelease identifiers must not have leading zeros.
+func validIdentifiers(s string, prerelease bool) bool {
+	for id := range strings.SplitSeq(s, ".") {
+		if id == "" {
+			return false
+		}
+
+		numeric := true
+
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				numeric = false
+			default:
+				return false
+			}
+		}
+
+		if prerelease && numeric && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compare returns the semver precedence of v relative to other.
+func (v semver) compare(other semver) int {
+	c := cmp.Or(
+		cmp.Compare(v.major, other.major),
+		cmp.Compare(v.minor, other.minor),
+		cmp.Compare(v.patch, other.patch),
+	)
+	if c != 0 {
+		return c
+	}
+
+	// A version without prerelease has higher precedence.
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := range min(len(v.prerelease), len(other.prerelease)) {
+		c = comparePrereleaseIdentifier(v.prerelease[i], other.prerelease[i])
+		if c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(v.prerelease), len(other.prerelease))
+}
+
+// comparePrereleaseIdentifier compares identifiers numerically if both are numeric,
+// otherwise lexically. Numeric identifiers have lower precedence.
+func comparePrereleaseIdentifier(a, b string) int {
+	aNum, aErr := strconv.ParseUint(a, 10, 64)
+	bNum, bErr := strconv.ParseUint(b, 10, 64)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(aNum, bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// semverComparator is a single constraint such as >=1.2.0.
+type semverComparator struct {
+	op      string
+	version semver
+}
+
+func (c semverComparator) allows(v semver) bool {
+	r := v.compare(c.version)
+
+	switch c.op {
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	case "!=":
+		return r != 0
+	default:
+		return r == 0
+	}
+}
+
+// parseSemverConstraint parses a range like ">=1.2.0 <2.0.0 || ^3.1.0".
+// Comparators separated by spaces or commas must all hold; || separates alternatives.
+// ^X.Y.Z allows changes that do not modify the left-most non-zero component, and
+// ~X.Y.Z allows patch-level changes.
+func parseSemverConstraint(constraint string) ([][]semverComparator, error) {
+	var alternatives [][]semverComparator
+
+	for alt := range strings.SplitSeq(constraint, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alt, ",", " "))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSemverRangeSyntax, constraint)
+		}
+
+		var comparators []semverComparator
+
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+
+			// Allow a space between operator and version, e.g. ">= 1.2.0".
+			if strings.Trim(field, "<>=!^~") == "" && i+1 < len(fields) {
+				field += fields[i+1]
+				i++
+			}
+
+			cs, ok := parseSemverComparator(field)
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidSemverRangeSyntax, constraint)
+			}
+
+			comparators = append(comparators, cs...)
+		}
+
+		alternatives = append(alternatives, comparators)
+	}
+
+	return alternatives, nil
+}
+
+// parseSemverComparator parses one comparator. Caret and tilde ranges expand
+// into a lower and an upper bound.
+func parseSemverComparator(s string) ([]semverComparator, bool) {
+	op := ""
+
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(s, candidate); ok {
+			op, s = candidate, rest
+
+			break
+		}
+	}
+
+	v, ok := parseSemver(s)
+	if !ok {
+		return nil, false
+	}
+
+	// Upper bounds use the lowest prerelease, so ^1.2.0 excludes 2.0.0-rc.1.
+	lowest := []string{"0"}
+
+	switch op {
+	case "^":
+		upper := semver{major: v.major + 1, prerelease: lowest}
+
+		switch {
+		case v.major == 0 && v.minor == 0:
+			upper = semver{patch: v.patch + 1, prerelease: lowest}
+		case v.major == 0:
+			upper = semver{minor: v.minor + 1, prerelease: lowest}
+		}
+
+		return []semverComparator{{">=", v}, {"<", upper}}, true
+
+	case "~":
+		upper := semver{major: v.major, minor: v.minor + 1, prerelease: lowest}
+
+		return []semverComparator{{">=", v}, {"<", upper}}, true
+
+	case "":
+		return []semverComparator{{"=", v}}, true
+
+	default:
+		return []semverComparator{{op, v}}, true
+	}
+}
+
+// semverMatcher matches strings that are valid semantic versions, optionally
+// within a constraint.
+type semverMatcher struct {
+	constraint   string
+	alternatives [][]semverComparator
+}
+
+func (m semverMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	v, ok := parseSemver(s)
+	if !ok {
+		return false
+	}
+
+	if m.alternatives == nil {
+		return true
+	}
+
+	for _, comparators := range m.alternatives {
+		allowed := true
+
+		for _, c := range comparators {
+			if !c.allows(v) {
+				allowed = false
+
+				break
+			}
+		}
+
+		if allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m semverMatcher) String() string {
+	if m.alternatives == nil {
+		return "{{anySemver}}"
+	}
+
+	return fmt.Sprintf("{{semverRange %q}}", m.constraint)
+}
+
+func (m semverMatcher) explain(actual any) string {
+	s, ok := actual.(string)
+	if !ok {
+		return expectedType("string", actual)
+	}
+
+	if _, ok := parseSemver(s); !ok {
+		return "value is not a valid semantic version"
+	}
+
+	return fmt.Sprintf("version %s does not satisfy %q", s, m.constraint)
+}
+
+// AnySemver returns a matcher that matches strings that are valid semantic versions,
+// e.g. "1.2.3" or "2.0.0-rc.1+build.5". A leading "v" is not allowed.
+func AnySemver() Matcher {
+	return semverMatcher{}
+}
+
+// SemverRange returns a matcher that matches semantic versions satisfying constraint,
+// e.g. ">=1.2.0 <2.0.0", "^1.2.0" or "~1.2.0 || ^2.0.0".
+// Comparators are >, >=, <, <=, =, != as well as ^ and ~ ranges.
+func SemverRange(constraint string) (Matcher, error) {
+	alternatives, err := parseSemverConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	return semverMatcher{constraint: constraint, alternatives: alternatives}, nil
+}
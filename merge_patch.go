@@ -0,0 +1,65 @@
+package testastic
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to base and returns the
+// merged document. Use it to compute the expected result of a PATCH request locally
+// and compare it against the server's response with AssertJSONEqual.
+//
+// Example:
+//
+//	merged, err := testastic.ApplyMergePatch(base, patch)
+//	testastic.NoError(t, err)
+//	testastic.AssertJSONEqual(t, merged, resp.Body)
+func ApplyMergePatch(base, patch []byte) ([]byte, error) {
+	baseData, err := decodeJSON(base, true)
+	if err != nil {
+		return nil, fmt.Errorf("merge patch base: %w", err)
+	}
+
+	patchData, err := decodeJSON(patch, true)
+	if err != nil {
+		return nil, fmt.Errorf("merge patch: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatch(baseData, patchData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged JSON: %w", err)
+	}
+
+	return merged, nil
+}
+
+// mergePatch implements the MergePatch algorithm from RFC 7386, section 2.
+// A non-object patch replaces the target; null members in an object patch remove keys.
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+
+	result := make(map[string]any, len(targetObj))
+	for key, val := range targetObj {
+		result[key] = val
+	}
+
+	for key, val := range patchObj {
+		if val == nil {
+			delete(result, key)
+
+			continue
+		}
+
+		result[key] = mergePatch(result[key], val)
+	}
+
+	return result
+}
@@ -2,21 +2,98 @@ package testastic
 
 import (
 	"flag"
+	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // Config holds the configuration for JSON comparison.
 type Config struct {
+	AllowExtraFields      bool
+	Captures              map[string]any
+	ContextLines          int
+	Coverage              *Coverage
+	DetectTautology       bool
+	DiffFormatJSON        bool
+	DiscriminatedUnions   map[string]DiscriminatedUnion
+	Epsilon               float64
+	EpsilonPaths          map[string]float64
+	FieldAliases          map[string]string
 	IgnoreArrayOrder      bool
 	IgnoreArrayOrderPaths []string
+	IgnoreStreamOrder     bool
+	IgnoredFieldPatterns  []string
 	IgnoredFields         []string
+	LeafValuesOnly        bool
+	MatchersAt            map[string]Matcher
+	MaxDiffs              int
+	Output                io.Writer
+	Record                bool
+	ReportAddedLeaves     bool
+	SignificantFigures    int
+	SortArrayKeys         map[string]string
+	StrictNumberTypes     bool
+	TreatNullAsAbsent     bool
 	Update                bool
+	UseJSONNumber         bool
+
+	refs   map[string]pendingRef  // Ref matchers of the current document, keyed by path
+	shapes map[string]parsedShape // Parsed discriminated union shape files, keyed by file
 }
 
 // Option is a functional option for configuring JSON comparison.
 type Option func(*Config)
 
+// Coverage records which expected paths a comparison checked. Compared holds the
+// leaf paths checked against the actual value, including matchers other than ignore.
+// Ignored holds the paths skipped by {{ignore}}, Ignore() or IgnoreFields options.
+// Both are sorted and free of duplicates.
+type Coverage struct {
+	Compared []string
+	Ignored  []string
+}
+
+// add records path as compared or ignored. It is a no-op on a nil Coverage.
+func (c *Coverage) add(path string, ignored bool) {
+	if c == nil {
+		return
+	}
+
+	list := &c.Compared
+	if ignored {
+		list = &c.Ignored
+	}
+
+	i, found := slices.BinarySearch(*list, path)
+	if !found {
+		*list = slices.Insert(*list, i, path)
+	}
+}
+
+// CoverageReport records into c which expected paths the comparison checked and
+// which it skipped, to find expected files that have become mostly ignored.
+//
+// Example:
+//
+//	var cov testastic.Coverage
+//	testastic.AssertJSON(t, "testdata/user.expected.json", body, testastic.CoverageReport(&cov))
+//	t.Logf("compared %d, ignored %d", len(cov.Compared), len(cov.Ignored))
+func CoverageReport(c *Coverage) Option {
+	return func(cfg *Config) {
+		cfg.Coverage = c
+	}
+}
+
+// DiscriminatedUnion describes a JSON value whose shape is selected by a sibling
+// discriminator field, e.g. a "data" object whose shape depends on "type".
+type DiscriminatedUnion struct {
+	Field  string            // Discriminator field name in the enclosing object
+	Shapes map[string]string // Discriminator value to expected shape file
+}
+
 // IgnoreFields excludes the specified fields from comparison.
 // Fields can be simple names or JSON paths (e.g., "$.user.id").
 func IgnoreFields(fields ...string) Option {
@@ -25,6 +102,38 @@ func IgnoreFields(fields ...string) Option {
 	}
 }
 
+// FieldAlias reads the value for expectedPath from actualKey in the same actual object,
+// e.g. FieldAlias("$.user.emailAddress", "email") after a field rename. If actualKey
+// is absent, the original key is used, so one expected file serves both versions.
+// If actual has both keys, the original key is not compared and is reported as an
+// added field unless AllowExtraFields is set. Differences are reported at
+// expectedPath with the alias noted in the reason.
+func FieldAlias(expectedPath, actualKey string) Option {
+	return func(c *Config) {
+		if c.FieldAliases == nil {
+			c.FieldAliases = make(map[string]string)
+		}
+
+		c.FieldAliases[expectedPath] = actualKey
+	}
+}
+
+// IgnoreStreamOrder makes AssertJSONStream match documents regardless of their order.
+func IgnoreStreamOrder() Option {
+	return func(c *Config) {
+		c.IgnoreStreamOrder = true
+	}
+}
+
+// IgnoreFieldsMatching excludes fields whose JSON path matches any of the patterns.
+// In a pattern, [*] matches any array index and * matches any single object key,
+// e.g. "$.items[*].id" or "$.*.createdAt". Patterns are anchored to the whole path.
+func IgnoreFieldsMatching(patterns ...string) Option {
+	return func(c *Config) {
+		c.IgnoredFieldPatterns = append(c.IgnoredFieldPatterns, patterns...)
+	}
+}
+
 // IgnoreArrayOrder makes array comparison order-insensitive globally.
 func IgnoreArrayOrder() Option {
 	return func(c *Config) {
@@ -39,6 +148,194 @@ func IgnoreArrayOrderAt(path string) Option {
 	}
 }
 
+// SortArrayByKey sorts the arrays of objects at the specified JSON path by the value
+// of key in both expected and actual before an ordered comparison. Unlike
+// IgnoreArrayOrderAt, index-based diffs stay meaningful when an item changes.
+// Items missing the key are kept after the sorted ones in their original order.
+func SortArrayByKey(path, key string) Option {
+	return func(c *Config) {
+		if c.SortArrayKeys == nil {
+			c.SortArrayKeys = make(map[string]string)
+		}
+
+		c.SortArrayKeys[path] = key
+	}
+}
+
+// WithMatcherAt applies a matcher at the specified JSON path, replacing the expected value there.
+// This allows programmatic matchers without template syntax, e.g.
+// WithMatcherAt("$.user.name", String().NonEmpty().MaxLen(64)).
+func WithMatcherAt(path string, m Matcher) Option {
+	return func(c *Config) {
+		if c.MatchersAt == nil {
+			c.MatchersAt = make(map[string]Matcher)
+		}
+
+		c.MatchersAt[path] = m
+	}
+}
+
+// AllowExtraFields tolerates object fields that exist in actual but not in expected,
+// making the expected file a subset contract. Fields missing from actual still fail,
+// and arrays are compared as usual.
+func AllowExtraFields() Option {
+	return func(c *Config) {
+		c.AllowExtraFields = true
+	}
+}
+
+// LeafValuesOnly compares only scalar values that exist on both sides and ignores
+// everything present on one side only: missing or extra object keys, and values that
+// are an object or array on one side but not the other. Ordered arrays are compared
+// index by index up to the shorter length, so trailing elements on either side are
+// ignored. Unordered arrays pair up to the shorter length; elements left without a
+// partner are ignored. Matchers still apply wherever the expected file has one.
+func LeafValuesOnly() Option {
+	return func(c *Config) {
+		c.LeafValuesOnly = true
+	}
+}
+
+// ContextLines collapses unchanged lines in the inline diff of a failure to at most
+// n lines around each change, like a unified diff. Skipped runs are shown as
+// "@@ 12 unchanged lines @@". By default the full document is shown.
+func ContextLines(n int) Option {
+	return func(c *Config) {
+		c.ContextLines = max(n, 0)
+	}
+}
+
+// TreatNullAsAbsent makes an object key with a null value equivalent to a missing
+// key, on either side. Use it when serializers differ in omitting null fields or
+// writing them explicitly. Non-null values are still compared.
+func TreatNullAsAbsent() Option {
+	return func(c *Config) {
+		c.TreatNullAsAbsent = true
+	}
+}
+
+// MaxDiffs limits a failure report to the first n differences by path, followed by
+// a note on how many were omitted. The comparison still covers the whole document.
+func MaxDiffs(n int) Option {
+	return func(c *Config) {
+		c.MaxDiffs = n
+	}
+}
+
+// FirstDiffOnly limits a failure report to the first difference. It is shorthand
+// for MaxDiffs(1).
+func FirstDiffOnly() Option {
+	return MaxDiffs(1)
+}
+
+// WithOutput additionally writes the failure text of JSON assertions to w, e.g. to
+// keep diffs as test artifacts. The assertion still reports the failure via Errorf,
+// so the test fails whether or not a writer is configured.
+func WithOutput(w io.Writer) Option {
+	return func(c *Config) {
+		c.Output = w
+	}
+}
+
+// ReportAddedLeaves expands values that exist only in actual into one difference per
+// leaf, each at its full path, instead of a single difference at the top added key.
+func ReportAddedLeaves() Option {
+	return func(c *Config) {
+		c.ReportAddedLeaves = true
+	}
+}
+
+// DetectTautology logs a warning when the expected and actual bytes are identical
+// before comparison, which may indicate a value was accidentally asserted against itself.
+func DetectTautology() Option {
+	return func(c *Config) {
+		c.DetectTautology = true
+	}
+}
+
+// DiffFormatJSON reports failures as a JSON array of differences (see FormatDiffJSON)
+// instead of the inline text diff. It can also be enabled with TESTASTIC_DIFF_FORMAT=json.
+func DiffFormatJSON() Option {
+	return func(c *Config) {
+		c.DiffFormatJSON = true
+	}
+}
+
+// StrictNumberTypes makes integer and decimal number representations distinct,
+// so 1 and 1.0 are reported as a type mismatch. Numbers are parsed as json.Number
+// to retain their representation. This is stricter than JSON semantics and opt-in.
+func StrictNumberTypes() Option {
+	return func(c *Config) {
+		c.StrictNumberTypes = true
+	}
+}
+
+// UseJSONNumber parses numbers in expected and actual as json.Number and compares
+// them exactly, so large integers such as 9007199254740993 are not rounded to float64.
+// Unlike StrictNumberTypes, 1 and 1.0 are still equal.
+func UseJSONNumber() Option {
+	return func(c *Config) {
+		c.UseJSONNumber = true
+	}
+}
+
+// Epsilon treats numbers as equal when they differ by at most tolerance.
+// It applies to all numeric comparisons in the document.
+func Epsilon(tolerance float64) Option {
+	return func(c *Config) {
+		c.Epsilon = tolerance
+	}
+}
+
+// EpsilonAt applies a numeric tolerance at the specified JSON path and its children.
+// It takes precedence over Epsilon.
+func EpsilonAt(path string, tolerance float64) Option {
+	return func(c *Config) {
+		if c.EpsilonPaths == nil {
+			c.EpsilonPaths = make(map[string]float64)
+		}
+
+		c.EpsilonPaths[path] = tolerance
+	}
+}
+
+// SignificantFigures treats numbers as equal when they agree after rounding both to
+// n significant figures, e.g. 1.2345e-9 and 1.2349e-9 at n=4. This suits measurement
+// data spanning many magnitudes. Where EpsilonAt or Epsilon applies, the absolute
+// tolerance is used instead: EpsilonAt takes precedence over Epsilon, which takes
+// precedence over SignificantFigures.
+func SignificantFigures(n int) Option {
+	return func(c *Config) {
+		c.SignificantFigures = n
+	}
+}
+
+// DiscriminatedUnionAt compares the value at path against an expected shape file
+// selected by the actual value of discriminatorField in the enclosing object.
+// Shape files support template matchers, including {"{{unordered}}": [...]}, like
+// regular expected files, and are read once per assertion. The expected value at
+// path must be {{ignore}} or {{anyValue}}, since the shape file defines it; any
+// other value is reported as a difference.
+//
+// Example:
+//
+//	testastic.DiscriminatedUnionAt("$.data", "type", map[string]string{
+//		"user":  "testdata/user_data.expected.json",
+//		"order": "testdata/order_data.expected.json",
+//	})
+func DiscriminatedUnionAt(path, discriminatorField string, shapes map[string]string) Option {
+	return func(c *Config) {
+		if c.DiscriminatedUnions == nil {
+			c.DiscriminatedUnions = make(map[string]DiscriminatedUnion)
+		}
+
+		c.DiscriminatedUnions[path] = DiscriminatedUnion{
+			Field:  discriminatorField,
+			Shapes: shapes,
+		}
+	}
+}
+
 // Update forces updating the expected file with the actual value.
 func Update() Option {
 	return func(c *Config) {
@@ -46,10 +343,64 @@ func Update() Option {
 	}
 }
 
-// newConfig creates a new Config with default values and applies options.
+// Record writes the actual value to the expected file without comparing, creating
+// or overwriting it. Unlike Update, which only writes files that differ, every
+// assertion writes its file, so a new suite can be bootstrapped in one run. Matchers
+// in existing files are replaced with the actual values. Also enabled by
+// TESTASTIC_RECORD=1.
+func Record() Option {
+	return func(c *Config) {
+		c.Record = true
+	}
+}
+
+// defaultOptions holds the options set by SetDefaultOptions.
+//
+//nolint:gochecknoglobals // Package-level defaults are the intended way to share policy.
+var defaultOptions struct {
+	sync.RWMutex
+
+	opts []Option
+}
+
+// SetDefaultOptions sets options applied to every JSON assertion and comparison before
+// the options passed at the call site, so call-site options still take precedence
+// where they set the same value. Options that add to a list, such as IgnoreFields, add
+// to the defaults. Calling it again replaces the defaults; calling it without options removes them.
+//
+// The defaults are process-global: they apply to all tests of the test binary, which
+// covers one package, and to code in other packages that uses testastic from it. Set
+// them once in TestMain, before any test runs, rather than from individual tests.
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		testastic.SetDefaultOptions(testastic.IgnoreArrayOrder(), testastic.Epsilon(1e-9))
+//		os.Exit(m.Run())
+//	}
+func SetDefaultOptions(opts ...Option) {
+	defaultOptions.Lock()
+	defer defaultOptions.Unlock()
+
+	defaultOptions.opts = slices.Clone(opts)
+}
+
+// newConfig creates a new Config with default values and applies the options set by
+// SetDefaultOptions followed by opts.
 func newConfig(opts ...Option) *Config {
 	cfg := &Config{
-		Update: shouldUpdate(),
+		ContextLines:   -1,
+		DiffFormatJSON: os.Getenv("TESTASTIC_DIFF_FORMAT") == "json",
+		Record:         shouldRecord(),
+		Update:         shouldUpdate(),
+	}
+
+	defaultOptions.RLock()
+	defaults := defaultOptions.opts
+	defaultOptions.RUnlock()
+
+	for _, opt := range defaults {
+		opt(cfg)
 	}
 
 	for _, opt := range opts {
@@ -82,6 +433,18 @@ func shouldUpdate() bool {
 	return false
 }
 
+// shouldRecord checks if record mode is enabled by the TESTASTIC_RECORD environment variable.
+func shouldRecord() bool {
+	env := os.Getenv("TESTASTIC_RECORD")
+
+	return strings.ToLower(env) == "true" || env == "1"
+}
+
+// useNumber reports whether JSON numbers should be parsed as json.Number.
+func (c *Config) useNumber() bool {
+	return c.StrictNumberTypes || c.UseJSONNumber
+}
+
 // shouldIgnoreArrayOrder checks if array order should be ignored at the given path.
 func (c *Config) shouldIgnoreArrayOrder(path string) bool {
 	if c.IgnoreArrayOrder {
@@ -97,8 +460,50 @@ func (c *Config) shouldIgnoreArrayOrder(path string) bool {
 	return false
 }
 
+// toleranceAt returns the numeric tolerance for the given path.
+// The most specific EpsilonAt path wins, falling back to the global Epsilon.
+// The second return value reports whether any tolerance option applies.
+func (c *Config) toleranceAt(path string) (float64, bool) {
+	tolerance := c.Epsilon
+	longest := -1
+
+	for p, tol := range c.EpsilonPaths {
+		if p == path || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			if len(p) > longest {
+				longest = len(p)
+				tolerance = tol
+			}
+		}
+	}
+
+	return tolerance, longest >= 0 || c.Epsilon != 0
+}
+
+// significantFiguresEqual reports whether expected and actual agree at the configured
+// number of significant figures. It is false if SignificantFigures is unset or an
+// absolute tolerance applies at path.
+func (c *Config) significantFiguresEqual(path string, expected, actual float64) bool {
+	if c.SignificantFigures <= 0 {
+		return false
+	}
+
+	if _, ok := c.toleranceAt(path); ok {
+		return false
+	}
+
+	digits := c.SignificantFigures - 1
+
+	return strconv.FormatFloat(expected, 'e', digits, 64) == strconv.FormatFloat(actual, 'e', digits, 64)
+}
+
 // isFieldIgnored checks if a field at the given path should be ignored.
 func (c *Config) isFieldIgnored(path string) bool {
+	for _, p := range c.IgnoredFieldPatterns {
+		if matchPathPattern(p, path) {
+			return true
+		}
+	}
+
 	for _, f := range c.IgnoredFields {
 		// Exact match
 		if f == path {
@@ -113,3 +518,26 @@ func (c *Config) isFieldIgnored(path string) bool {
 
 	return false
 }
+
+// matchPathPattern reports whether a JSON path matches a pattern segment by segment.
+// A "*" key segment matches any key and a "[*]" segment matches any index.
+func matchPathPattern(pattern, path string) bool {
+	patSegs := splitPath(pattern)
+	pathSegs := splitPath(path)
+
+	if len(patSegs) != len(pathSegs) {
+		return false
+	}
+
+	for i, seg := range patSegs {
+		switch {
+		case seg == pathSegs[i]:
+		case seg == "[*]" && strings.HasPrefix(pathSegs[i], "["):
+		case seg == ".*" && strings.HasPrefix(pathSegs[i], "."):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
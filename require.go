@@ -0,0 +1,58 @@
+package testastic
+
+// Require returns a Reporter that stops the test at the first failure. Assertions
+// report failures with Errorf, which marks the test as failed but lets it continue,
+// like the assert package of testify. Through Require, Errorf calls Fatalf instead,
+// like testify's require package, so a wrong early fixture does not cause a cascade
+// of follow-up failures. Any assertion in this package accepts the returned Reporter.
+//
+// Example:
+//
+//	require := testastic.Require(t)
+//	testastic.NoError(require, err)
+//	testastic.AssertJSON(require, "testdata/user.expected.json", resp.Body)
+func Require(tb Reporter) Reporter {
+	return requireReporter{Reporter: tb}
+}
+
+// requireReporter turns failures reported with Errorf into fatal ones.
+type requireReporter struct {
+	Reporter
+}
+
+func (r requireReporter) Errorf(format string, args ...any) {
+	r.Reporter.Helper()
+	r.Reporter.Fatalf(format, args...)
+}
+
+// RequireJSON is like AssertJSON but stops the test if the assertion fails.
+//
+// Example:
+//
+//	testastic.RequireJSON(t, "testdata/fixture.expected.json", fixture)
+func RequireJSON[T any](tb Reporter, expectedFile string, actual T, opts ...Option) {
+	tb.Helper()
+
+	AssertJSON(Require(tb), expectedFile, actual, opts...)
+}
+
+// RequireEqual is like Equal but stops the test if the assertion fails.
+func RequireEqual[T comparable](tb Reporter, expected, actual T, msgAndArgs ...any) {
+	tb.Helper()
+
+	Equal(Require(tb), expected, actual, msgAndArgs...)
+}
+
+// RequireNoError is like NoError but stops the test if the assertion fails.
+func RequireNoError(tb Reporter, err error, msgAndArgs ...any) {
+	tb.Helper()
+
+	NoError(Require(tb), err, msgAndArgs...)
+}
+
+// RequireNotNil is like NotNil but stops the test if the assertion fails.
+func RequireNotNil(tb Reporter, value any, msgAndArgs ...any) {
+	tb.Helper()
+
+	NotNil(Require(tb), value, msgAndArgs...)
+}
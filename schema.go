@@ -0,0 +1,88 @@
+package testastic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoSchemaExamples is returned when a JSON Schema has no examples to compare against.
+var ErrNoSchemaExamples = errors.New("schema has no examples")
+
+// AssertMatchesSchemaExample asserts that actual matches at least one entry of the
+// top-level "examples" array of a JSON Schema file. Examples are compared with
+// subset semantics: fields in actual that are not in the example are allowed.
+// T can be: []byte, string, io.Reader, or any struct (auto-marshaled).
+//
+// Example:
+//
+//	testastic.AssertMatchesSchemaExample(t, "schemas/user.schema.json", resp.Body)
+func AssertMatchesSchemaExample[T any](tb Reporter, schemaFile string, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(append([]Option{AllowExtraFields()}, opts...)...)
+
+	examples, err := loadSchemaExamples(schemaFile, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	closest := -1
+
+	var closestDiffs []Difference
+
+	for i, example := range examples {
+		diffs := compareDocument(example, actualData, "$", cfg)
+		if len(diffs) == 0 {
+			return
+		}
+
+		if closest < 0 || len(diffs) < len(closestDiffs) {
+			closest, closestDiffs = i, diffs
+		}
+	}
+
+	tb.Errorf(
+		"testastic: assertion failed\n\n  AssertMatchesSchemaExample (%s)\n"+
+			"    no example matched, closest is examples[%d]:\n%s",
+		schemaFile, closest, formatDiffInline(examples[closest], actualData, cfg.ContextLines),
+	)
+}
+
+// loadSchemaExamples reads a JSON Schema file and returns its top-level examples.
+func loadSchemaExamples(path string, useNumber bool) ([]any, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // Path is controlled by test code.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	data, err := decodeJSON(content, useNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema file as JSON: %w", err)
+	}
+
+	schema, _ := data.(map[string]any)
+	examples, _ := schema["examples"].([]any)
+
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoSchemaExamples, path)
+	}
+
+	return examples, nil
+}
@@ -12,13 +12,21 @@ import (
 // ErrUnknownPlaceholder is returned when a placeholder is not found in the matcher map.
 var ErrUnknownPlaceholder = errors.New("unknown placeholder")
 
+// ErrInvalidUnorderedSyntax is returned when an {{unordered}} wrapper is malformed.
+var ErrInvalidUnorderedSyntax = errors.New("invalid unordered syntax")
+
 // ExpectedJSON represents a parsed expected file with matchers.
 type ExpectedJSON struct {
-	Data     any               // Parsed JSON with Matcher objects in place of template expressions
-	Matchers map[string]string // Map of placeholder to original template expression
-	Raw      string            // Original file content for update operations
+	Data           any               // Parsed JSON with Matcher objects in place of template expressions
+	Matchers       map[string]string // Map of placeholder to original template expression
+	Raw            string            // Original file content for update operations
+	UnorderedPaths []string          // Paths of arrays wrapped in {"{{unordered}}": [...]}
 }
 
+// unorderedKeyword marks an order-insensitive array in an expected file,
+// e.g. {"tags": {"{{unordered}}": ["a", "b"]}}.
+const unorderedKeyword = "unordered"
+
 // matcherPlaceholderPrefix is the prefix used for matcher placeholders.
 const matcherPlaceholderPrefix = "__TESTASTIC_MATCHER_"
 
@@ -27,23 +35,54 @@ var templateExprRegex = regexp.MustCompile(`"?\{\{((?:[^}` + "`" + `]+|` + "`" +
 
 // ParseExpectedFile reads and parses an expected file, replacing template expressions with matchers.
 func ParseExpectedFile(path string) (*ExpectedJSON, error) {
+	return parseExpectedFile(path, false)
+}
+
+// ParseExpectedString parses an expected JSON string with template expressions.
+func ParseExpectedString(content string) (*ExpectedJSON, error) {
+	return parseExpectedString(content, false)
+}
+
+// parseExpectedFile reads and parses an expected file, optionally preserving numbers as json.Number.
+func parseExpectedFile(path string, useNumber bool) (*ExpectedJSON, error) {
 	content, err := os.ReadFile(path) //nolint:gosec // Path is controlled by test code.
 	if err != nil {
 		return nil, fmt.Errorf("failed to read expected file: %w", err)
 	}
 
-	return ParseExpectedString(string(content))
+	return parseExpectedString(string(content), useNumber)
 }
 
-// ParseExpectedString parses an expected JSON string with template expressions.
-func ParseExpectedString(content string) (*ExpectedJSON, error) {
+// parseExpectedString parses an expected JSON string, optionally preserving numbers as json.Number.
+func parseExpectedString(content string, useNumber bool) (*ExpectedJSON, error) {
 	expected := &ExpectedJSON{
 		Matchers: make(map[string]string),
 		Raw:      content,
 	}
 
-	matcherIndex := 0
-	processedContent := templateExprRegex.ReplaceAllStringFunc(content, func(match string) string {
+	processedContent := substituteTemplateExprs(content, expected)
+
+	data, err := decodeJSON([]byte(processedContent), useNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected file as JSON: %w", err)
+	}
+
+	replaced, err := replacePlaceholders(data, "$", expected)
+	if err != nil {
+		return nil, err
+	}
+
+	expected.Data = replaced
+
+	return expected, nil
+}
+
+// substituteTemplateExprs replaces {{...}} expressions in content with quoted placeholder
+// strings, recording the original expressions in expected.Matchers.
+func substituteTemplateExprs(content string, expected *ExpectedJSON) string {
+	matcherIndex := len(expected.Matchers)
+
+	return templateExprRegex.ReplaceAllStringFunc(content, func(match string) string {
 		expr := match
 
 		// Strip surrounding quotes if the expression was quoted in JSON.
@@ -65,31 +104,27 @@ func ParseExpectedString(content string) (*ExpectedJSON, error) {
 
 		return placeholder
 	})
-
-	var data any
-
-	err := json.Unmarshal([]byte(processedContent), &data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse expected file as JSON: %w", err)
-	}
-
-	replaced, err := replacePlaceholders(data, expected.Matchers)
-	if err != nil {
-		return nil, err
-	}
-
-	expected.Data = replaced
-
-	return expected, nil
 }
 
 // replacePlaceholders walks the parsed JSON and replaces placeholder strings with Matcher objects.
-func replacePlaceholders(data any, matchers map[string]string) (any, error) {
+// Arrays wrapped in {"{{unordered}}": [...]} are unwrapped and their paths recorded.
+func replacePlaceholders(data any, path string, expected *ExpectedJSON) (any, error) {
 	switch v := data.(type) {
 	case map[string]any:
+		arr, ok, err := unwrapUnordered(v, path, expected.Matchers)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			expected.UnorderedPaths = append(expected.UnorderedPaths, path)
+
+			return replacePlaceholders(arr, path, expected)
+		}
+
 		result := make(map[string]any, len(v))
 		for key, val := range v {
-			replaced, err := replacePlaceholders(val, matchers)
+			replaced, err := replacePlaceholders(val, path+"."+key, expected)
 			if err != nil {
 				return nil, err
 			}
@@ -102,7 +137,7 @@ func replacePlaceholders(data any, matchers map[string]string) (any, error) {
 	case []any:
 		result := make([]any, len(v))
 		for i, val := range v {
-			replaced, err := replacePlaceholders(val, matchers)
+			replaced, err := replacePlaceholders(val, fmt.Sprintf("%s[%d]", path, i), expected)
 			if err != nil {
 				return nil, err
 			}
@@ -114,7 +149,7 @@ func replacePlaceholders(data any, matchers map[string]string) (any, error) {
 
 	case string:
 		if strings.HasPrefix(v, matcherPlaceholderPrefix) {
-			expr, ok := matchers[v]
+			expr, ok := expected.Matchers[v]
 			if !ok {
 				return nil, fmt.Errorf("%w: %s", ErrUnknownPlaceholder, v)
 			}
@@ -134,6 +169,24 @@ func replacePlaceholders(data any, matchers map[string]string) (any, error) {
 	}
 }
 
+// unwrapUnordered returns the array inside an {"{{unordered}}": [...]} wrapper.
+func unwrapUnordered(obj map[string]any, path string, matchers map[string]string) ([]any, bool, error) {
+	for key, val := range obj {
+		if matchers[key] != unorderedKeyword {
+			continue
+		}
+
+		arr, ok := val.([]any)
+		if !ok || len(obj) != 1 {
+			return nil, false, fmt.Errorf("%w: %s must wrap a single array", ErrInvalidUnorderedSyntax, path)
+		}
+
+		return arr, true, nil
+	}
+
+	return nil, false, nil
+}
+
 // ExtractMatcherPositions returns a map of JSON paths to their original template expressions.
 // This is used when updating expected files to preserve matchers.
 func (e *ExpectedJSON) ExtractMatcherPositions() map[string]string {
@@ -167,3 +220,48 @@ func extractMatcherPaths(data any, path string, positions map[string]string) {
 		}
 	}
 }
+
+// expectedTree converts a Go value into an expected JSON tree, keeping Matcher values
+// found in map[string]any and []any nodes. Other values are round-tripped through JSON.
+func expectedTree(v any, useNumber bool) (any, error) {
+	switch val := v.(type) {
+	case Matcher:
+		return val, nil
+
+	case map[string]any:
+		result := make(map[string]any, len(val))
+
+		for key, item := range val {
+			converted, err := expectedTree(item, useNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			result[key] = converted
+		}
+
+		return result, nil
+
+	case []any:
+		result := make([]any, len(val))
+
+		for i, item := range val {
+			converted, err := expectedTree(item, useNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = converted
+		}
+
+		return result, nil
+
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal expected value: %w", err)
+		}
+
+		return decodeJSON(data, useNumber)
+	}
+}
@@ -4,13 +4,23 @@
 package testastic
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
-	"testing"
 )
 
+// Reporter is the subset of testing.TB used by assertions to report results.
+// It is satisfied by testing.TB (*testing.T, *testing.B, *testing.F) and lets
+// assertions run in custom harnesses outside of go test.
+type Reporter interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
 // AssertJSON compares actual JSON against an expected JSON file.
 // T can be: []byte, string, io.Reader, or any struct (auto-marshaled).
 //
@@ -19,9 +29,7 @@ import (
 //	testastic.AssertJSON(t, "testdata/user.expected.json", resp.Body)
 //	testastic.AssertJSON(t, "testdata/user.expected.json", myUser)
 //	testastic.AssertJSON(t, "testdata/user.expected.json", jsonBytes)
-//
-//nolint:funlen // Main assertion function needs sequential validation steps.
-func AssertJSON[T any](tb testing.TB, expectedFile string, actual T, opts ...Option) {
+func AssertJSON[T any](tb Reporter, expectedFile string, actual T, opts ...Option) {
 	tb.Helper()
 
 	// Convert actual to []byte
@@ -32,8 +40,167 @@ func AssertJSON[T any](tb testing.TB, expectedFile string, actual T, opts ...Opt
 		return
 	}
 
-	// Build config
+	assertJSONFile(tb, "AssertJSON", expectedFile, actualBytes, newConfig(opts...))
+}
+
+// AssertJSONNotEqual asserts that actual differs from the expected file, e.g. to guard
+// that a response changed after an update. It runs the same comparison as AssertJSON,
+// so matchers in the expected file and ignore options still apply, and fails only if
+// there are no differences. The expected file must exist; it is never created or updated.
+//
+// Example:
+//
+//	testastic.AssertJSONNotEqual(t, "testdata/user_before.expected.json", resp.Body)
+func AssertJSONNotEqual[T any](tb Reporter, expectedFile string, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+
+	expected, err := parseExpectedFile(expectedFile, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, expected.UnorderedPaths...)
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	if len(compareDocument(expected.Data, actualData, "$", cfg)) == 0 {
+		fail(tb, "AssertJSONNotEqual ("+expectedFile+")", "a document that differs from the expected file",
+			"no differences", nil)
+	}
+}
+
+// CheckJSON is like AssertJSON but also returns the differences it reported, sorted
+// by path. The result is empty on success, when the expected file was created or
+// updated, and when the comparison could not run. Failures are still reported to tb.
+//
+// Example:
+//
+//	diffs := testastic.CheckJSON(t, "testdata/user.expected.json", resp.Body)
+//	for _, d := range diffs {
+//		t.Logf("%s: %s", d.Path, d.Type)
+//	}
+func CheckJSON[T any](tb Reporter, expectedFile string, actual T, opts ...Option) []Difference {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return nil
+	}
+
+	return assertJSONFile(tb, "CheckJSON", expectedFile, actualBytes, newConfig(opts...))
+}
+
+// AssertJSONCapture is like AssertJSON but also returns the actual values at the given
+// JSON paths, keyed by path, e.g. a generated id to use in a follow-up request.
+// Values are only captured once the assertion passed; after a failure it returns nil.
+// A path that does not exist in the actual JSON fails the test with Fatalf, since later
+// steps depend on it.
+//
+// Example:
+//
+//	vals := testastic.AssertJSONCapture(t, "testdata/create.expected.json", resp.Body, []string{"$.id"})
+//	get := client.Get("/users/" + vals["$.id"].(string))
+func AssertJSONCapture[T any](
+	tb Reporter, expectedFile string, actual T, paths []string, opts ...Option,
+) map[string]any {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return nil
+	}
+
 	cfg := newConfig(opts...)
+	tracker := &failureTracker{Reporter: tb}
+
+	assertJSONFile(tracker, "AssertJSONCapture", expectedFile, actualBytes, cfg)
+
+	if tracker.failed {
+		return nil
+	}
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return nil
+	}
+
+	values := make(map[string]any, len(paths))
+
+	for _, path := range paths {
+		val, ok := valueAtPath(actualData, path)
+		if !ok {
+			tb.Fatalf("testastic: AssertJSONCapture: path %s not found in actual JSON", path)
+
+			return nil
+		}
+
+		values[path] = val
+	}
+
+	return values
+}
+
+// failureTracker records whether a failure was reported through it, for reporters
+// whose Fatalf does not stop the caller.
+type failureTracker struct {
+	Reporter
+
+	failed bool
+}
+
+func (f *failureTracker) Errorf(format string, args ...any) {
+	f.Reporter.Helper()
+	f.failed = true
+	f.Reporter.Errorf(format, args...)
+}
+
+func (f *failureTracker) Fatalf(format string, args ...any) {
+	f.Reporter.Helper()
+	f.failed = true
+	f.Reporter.Fatalf(format, args...)
+}
+
+// assertJSONFile compares actual JSON bytes against an expected file and reports
+// failures under the given assertion name. It handles creating and updating the file
+// and returns the reported differences, sorted by path.
+//
+//nolint:funlen // Main assertion flow needs sequential validation steps.
+func assertJSONFile(tb Reporter, name, expectedFile string, actualBytes []byte, cfg *Config) []Difference {
+	tb.Helper()
+
+	if cfg.Record {
+		recordErr := createExpectedFile(expectedFile, actualBytes)
+		if recordErr != nil {
+			tb.Fatalf("testastic: failed to record expected file: %v", recordErr)
+		}
+
+		recordUpdate("recorded", expectedFile)
+		tb.Logf("testastic: recorded expected file %s", expectedFile)
+
+		return nil
+	}
 
 	// Check if expected file exists
 	_, statErr := os.Stat(expectedFile)
@@ -44,9 +211,10 @@ func AssertJSON[T any](tb testing.TB, expectedFile string, actual T, opts ...Opt
 				tb.Fatalf("testastic: failed to create expected file: %v", createErr)
 			}
 
+			recordUpdate("created", expectedFile)
 			tb.Logf("testastic: created expected file %s", expectedFile)
 
-			return
+			return nil
 		}
 
 		tb.Fatalf(
@@ -54,27 +222,31 @@ func AssertJSON[T any](tb testing.TB, expectedFile string, actual T, opts ...Opt
 			expectedFile,
 		)
 
-		return
+		return nil
 	}
 
 	// Parse expected file
-	expected, err := ParseExpectedFile(expectedFile)
+	expected, err := parseExpectedFile(expectedFile, cfg.useNumber())
 	if err != nil {
 		tb.Fatalf("testastic: %v", err)
 
-		return
+		return nil
 	}
 
+	warnTautology(tb, cfg, name, []byte(expected.Raw), actualBytes)
+
+	cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, expected.UnorderedPaths...)
+
 	// Parse actual JSON
-	actualData, err := parseActualJSON(actualBytes)
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
 	if err != nil {
 		tb.Fatalf("testastic: %v", err)
 
-		return
+		return nil
 	}
 
 	// Compare
-	diffs := compare(expected.Data, actualData, "$", cfg)
+	diffs := compareDocument(expected.Data, actualData, "$", cfg)
 
 	// If update mode and there are differences, update the file
 	if cfg.Update && len(diffs) > 0 {
@@ -83,18 +255,324 @@ func AssertJSON[T any](tb testing.TB, expectedFile string, actual T, opts ...Opt
 			tb.Fatalf("testastic: failed to update expected file: %v", updateErr)
 		}
 
+		recordUpdate("updated", expectedFile)
 		tb.Logf("testastic: updated expected file %s", expectedFile)
 
-		return
+		return nil
 	}
 
 	// Report differences
 	if len(diffs) > 0 {
-		sortDiffs(diffs)
+		reportJSONFailure(tb, cfg, name+" ("+expectedFile+")", expected.Data, actualData, diffs)
+	}
+
+	return diffs
+}
+
+// AssertJSONString compares actual JSON against an inline expected JSON string.
+// The expected string supports the same template matchers as expected files.
+// Update mode does not apply since there is no file to write.
+//
+// Example:
+//
+//	testastic.AssertJSONString(t, `{"id": "{{anyString}}", "name": "Alice"}`, resp.Body)
+func AssertJSONString[T any](tb Reporter, expected string, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+
+	exp, err := parseExpectedString(expected, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	warnTautology(tb, cfg, "AssertJSONString", []byte(expected), actualBytes)
+
+	cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, exp.UnorderedPaths...)
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	diffs := compareDocument(exp.Data, actualData, "$", cfg)
+	if len(diffs) > 0 {
+		reportJSONFailure(tb, cfg, "AssertJSONString", exp.Data, actualData, diffs)
+	}
+}
+
+// AssertJSONEqual compares two in-memory values as JSON without an expected file.
+// Both sides are converted like AssertJSON's actual value. Template matchers are not
+// evaluated, but all Ignore* options apply.
+//
+// Example:
+//
+//	testastic.AssertJSONEqual(t, User{Name: "Alice"}, got)
+func AssertJSONEqual[T any](tb Reporter, expected, actual T, opts ...Option) {
+	tb.Helper()
+
+	expectedBytes, err := toBytes(expected)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert expected to bytes: %v", err)
+
+		return
+	}
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+	warnTautology(tb, cfg, "AssertJSONEqual", expectedBytes, actualBytes)
+
+	expectedData, err := parseActualJSON(expectedBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	diffs := compareDocument(expectedData, actualData, "$", cfg)
+	if len(diffs) > 0 {
+		reportJSONFailure(tb, cfg, "AssertJSONEqual", expectedData, actualData, diffs)
+	}
+}
+
+// CompareJSON compares actual JSON against expected JSON content without a Reporter.
+// The expected content may contain template matchers. Returns the differences sorted by path.
+func CompareJSON(expected, actual []byte, opts ...Option) ([]Difference, error) {
+	cfg := newConfig(opts...)
+
+	exp, err := parseExpectedString(string(expected), cfg.useNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, exp.UnorderedPaths...)
+
+	actualData, err := parseActualJSON(actual, cfg.useNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := compareDocument(exp.Data, actualData, "$", cfg)
+	sortDiffs(diffs)
+
+	return diffs, nil
+}
+
+// CompareJSONValue compares actual JSON against an expected Go value without a file.
+// The expected value may interleave concrete Go values with Matcher values in
+// map[string]any and []any nodes; all other values are converted through
+// encoding/json. Matchers must be inserted as Matcher values such as AnyString(),
+// not as "{{anyString}}" strings, which are compared literally.
+//
+// Example:
+//
+//	diffs, err := testastic.CompareJSONValue(map[string]any{
+//		"id":      testastic.AnyString(),
+//		"name":    "Alice",
+//		"created": testastic.AnyTimestamp(),
+//	}, body)
+func CompareJSONValue(expected any, actual []byte, opts ...Option) ([]Difference, error) {
+	cfg := newConfig(opts...)
+
+	expectedData, err := expectedTree(expected, cfg.useNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	actualData, err := parseActualJSON(actual, cfg.useNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := compareDocument(expectedData, actualData, "$", cfg)
+	sortDiffs(diffs)
+
+	return diffs, nil
+}
+
+// AssertJSONValue compares actual JSON against an expected Go value that may contain
+// Matcher values. See CompareJSONValue for how the expected value is interpreted.
+//
+// Example:
+//
+//	testastic.AssertJSONValue(t, map[string]any{"id": testastic.AnyInt(), "name": "Alice"}, resp.Body)
+func AssertJSONValue[T any](tb Reporter, expected any, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+
+	expectedData, err := expectedTree(expected, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	diffs := compareDocument(expectedData, actualData, "$", cfg)
+	if len(diffs) > 0 {
+		reportJSONFailure(tb, cfg, "AssertJSONValue", expectedData, actualData, diffs)
+	}
+}
+
+// AssertJSONPath asserts that the value at a JSON path like $.user.roles[0] in actual
+// matches expected, without an expected file for the whole document. Expected is
+// interpreted like in CompareJSONValue, so it may contain Matcher values. If the path
+// does not resolve, the first missing segment is reported.
+//
+// Example:
+//
+//	testastic.AssertJSONPath(t, resp.Body, "$.user.roles[0]", "admin")
+//	testastic.AssertJSONPath(t, resp.Body, "$.user.id", testastic.AnyInt())
+func AssertJSONPath[T any](tb Reporter, actual T, path string, expected any, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+
+	expectedData, err := expectedTree(expected, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	actualData, err := parseActualJSON(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	value, missing := lookupPath(actualData, path)
+	if missing != "" {
 		tb.Errorf(
-			"testastic: assertion failed\n\n  AssertJSON (%s)\n%s",
-			expectedFile, FormatDiffInline(expected.Data, actualData),
+			"testastic: assertion failed\n\n  AssertJSONPath (%s)\n    error: %s not found in actual JSON",
+			path, missing,
 		)
+
+		return
+	}
+
+	diffs := compareDocument(expectedData, value, path, cfg)
+	if len(diffs) > 0 {
+		reportJSONFailure(tb, cfg, "AssertJSONPath ("+path+")", expectedData, value, diffs)
+	}
+}
+
+// CompareJSONWithStats is like CompareJSON but also returns summary statistics
+// about the differences, e.g. for benchmarks or meta-tests on diff characteristics.
+func CompareJSONWithStats(expected, actual []byte, opts ...Option) ([]Difference, DiffStats, error) {
+	diffs, err := CompareJSON(expected, actual, opts...)
+	if err != nil {
+		return nil, DiffStats{}, err
+	}
+
+	return diffs, NewDiffStats(diffs), nil
+}
+
+// reportJSONFailure reports a failed JSON assertion via tb.Errorf. With WithOutput,
+// the same failure text is also written to the configured writer.
+func reportJSONFailure(tb Reporter, cfg *Config, name string, expected, actual any, diffs []Difference) {
+	tb.Helper()
+
+	msg := fmt.Sprintf(
+		"testastic: assertion failed\n\n  %s\n%s",
+		name, formatJSONFailure(cfg, expected, actual, diffs),
+	)
+
+	if cfg.Output != nil {
+		_, err := io.WriteString(cfg.Output, msg)
+		if err != nil {
+			tb.Logf("testastic: failed to write to output: %v", err)
+		}
+	}
+
+	tb.Errorf("%s", msg)
+}
+
+// formatJSONFailure formats the differences of a failed JSON assertion, either as an
+// inline diff followed by failure reasons or, with DiffFormatJSON, as a JSON array.
+//
+// The differences are sorted by path first, so the output does not depend on map
+// iteration order. With MaxDiffs, at most that many differences are shown, followed
+// by a count of the omitted ones. The full inline diff is replaced by the path list
+// then, since it would show every difference.
+func formatJSONFailure(cfg *Config, expected, actual any, diffs []Difference) string {
+	sortDiffs(diffs)
+
+	truncated := cfg.MaxDiffs > 0 && len(diffs) > cfg.MaxDiffs
+
+	var note string
+
+	if truncated {
+		note = fmt.Sprintf("\n  ... and %d more differences\n", len(diffs)-cfg.MaxDiffs)
+		diffs = diffs[:cfg.MaxDiffs]
+	}
+
+	if cfg.DiffFormatJSON {
+		out, err := FormatDiffJSON(diffs)
+		if err == nil {
+			return out + "\n" + note
+		}
+	}
+
+	if truncated {
+		return FormatDiff(diffs) + note
+	}
+
+	return formatDiffInline(expected, actual, cfg.ContextLines) + formatReasons(diffs)
+}
+
+// warnTautology logs a warning if DetectTautology is set and expected and actual are identical.
+func warnTautology(tb Reporter, cfg *Config, name string, expected, actual []byte) {
+	tb.Helper()
+
+	if cfg.DetectTautology && bytes.Equal(expected, actual) {
+		tb.Logf("testastic: warning: %s expected and actual are identical; the assertion may be tautological", name)
 	}
 }
 
@@ -2,10 +2,16 @@ package testastic_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/monkescience/testastic"
 )
@@ -161,6 +167,116 @@ func TestAssertJSON_WithOneOfMatcher(t *testing.T) {
 	testastic.AssertJSON(t, expectedFile, actual)
 }
 
+func TestAssertJSONString_TypeMatcher(t *testing.T) {
+	// GIVEN: expected JSON declaring types with the type matcher
+	expected := `{"age": "{{type int}}", "tags": "{{type array}}", "meta": "{{type null}}"}`
+
+	// WHEN: asserting values of the declared types
+	// THEN: the test passes
+	testastic.AssertJSONString(t, expected, `{"age": 30, "tags": ["a"], "meta": null}`)
+
+	// WHEN: asserting a value of another type
+	r := &recordingReporter{}
+	testastic.AssertJSONString(r, expected, `{"age": "30", "tags": [], "meta": null}`)
+
+	// THEN: the mismatching field fails
+	testastic.Len(t, r.errors, 1)
+}
+
+func TestAssertJSONString_CaptureAndRef(t *testing.T) {
+	// GIVEN: expected JSON that captures a value and refers to it before and after
+	expected := `{
+		"echo": "{{ref \"rid\"}}",
+		"request_id": "{{capture \"rid\"}}",
+		"response": {"request_id": "{{ref \"rid\"}}"}
+	}`
+
+	// WHEN: the referring fields equal the captured value
+	captures := map[string]any{}
+	testastic.AssertJSONString(t, expected,
+		`{"echo": "r-1", "request_id": "r-1", "response": {"request_id": "r-1"}}`,
+		testastic.WithCaptures(captures))
+
+	// THEN: the test passes and the captured value is returned
+	testastic.Equal(t, any("r-1"), captures["rid"])
+
+	// WHEN: a referring field differs
+	r := &recordingReporter{}
+	testastic.AssertJSONString(r, expected,
+		`{"echo": "r-1", "request_id": "r-1", "response": {"request_id": "r-2"}}`)
+
+	// THEN: the difference is reported at the ref
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], `captured as "rid"`)
+}
+
+func TestCompareJSON_RefWithoutCapture(t *testing.T) {
+	// GIVEN: a ref to a name that is never captured
+	expected := []byte(`{"id": "{{ref \"missing\"}}"}`)
+
+	// WHEN: comparing
+	diffs, err := testastic.CompareJSON(expected, []byte(`{"id": 1}`))
+
+	// THEN: the ref fails
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "$.id", diffs[0].Path)
+}
+
+func TestCompareJSON_RefInUnorderedArray(t *testing.T) {
+	// GIVEN: an unordered array where only one pairing satisfies a ref
+	expected := []byte(`{"owner": "{{capture \"x\"}}", "items": [{"id": "{{ref \"x\"}}"}, {"id": "{{anyString}}"}]}`)
+	actual := []byte(`{"owner": "B", "items": [{"id": "A"}, {"id": "B"}]}`)
+
+	for range 10 {
+		// WHEN: comparing with IgnoreArrayOrder
+		diffs, err := testastic.CompareJSON(expected, actual, testastic.IgnoreArrayOrder())
+
+		// THEN: the ref is paired with the element holding the captured value
+		testastic.NoError(t, err)
+		testastic.Empty(t, diffs)
+	}
+}
+
+func TestCompareJSON_CaptureInUnorderedBestMatch(t *testing.T) {
+	// GIVEN: an unordered array element with a capture that matches no actual element
+	// exactly, and a later candidate that differs more
+	expected := []byte(`{"items": [{"k": "{{capture \"x\"}}", "n": 9}], "ref": "{{ref \"x\"}}"}`)
+	actual := []byte(`{"items": [{"k": "A", "n": 9, "m": 1}, {"k": "B", "n": 0, "m": 2}], "ref": "A"}`)
+	captures := map[string]any{}
+
+	// WHEN: comparing with IgnoreArrayOrder
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.IgnoreArrayOrder(), testastic.WithCaptures(captures))
+
+	// THEN: the capture comes from the best match, so the ref resolves
+	testastic.NoError(t, err)
+	testastic.Equal(t, any("A"), captures["x"])
+
+	for _, d := range diffs {
+		if d.Path == "$.ref" {
+			t.Errorf("expected the ref to resolve, got %v", d)
+		}
+	}
+}
+
+func TestAssertJSONString_ArrayLen(t *testing.T) {
+	// GIVEN: expected JSON asserting only the length of an array
+	expected := `{"items": "{{arrayLen 3}}"}`
+
+	// WHEN: the array has that length
+	// THEN: the test passes regardless of contents
+	testastic.AssertJSONString(t, expected, `{"items": [1, "two", {"three": 3}]}`)
+
+	// WHEN: the array has another length, or the value is not an array
+	r := &recordingReporter{}
+	testastic.AssertJSONString(r, expected, `{"items": [1, 2]}`)
+	testastic.AssertJSONString(r, expected, `{"items": "abc"}`)
+
+	// THEN: both fail with the reason
+	testastic.Len(t, r.errors, 2)
+	testastic.Contains(t, r.errors[0], "expected array of length 3, got length 2")
+}
+
 func TestAssertJSON_NestedObjects(t *testing.T) {
 	// GIVEN: an expected JSON file with nested objects and matchers
 	dir := t.TempDir()
@@ -337,9 +453,67 @@ func TestParseMatcher(t *testing.T) {
 		{"anyFloat", false},
 		{"anyBool", false},
 		{"anyValue", false},
+		{"present", false},
+		{"arrayLen 3", false},
+		{`hasPrefix "https://"`, false},
+		{`hasSuffix ".png"`, false},
+		{`contains "error"`, false},
+		{"contains error", true},
+		{"hasPrefix https", true},
+		{`hasSuffix ""`, true},
+		{"arrayLen 0", false},
+		{"arrayLen -1", true},
+		{"arrayLen three", true},
 		{"ignore", false},
 		{"regex `^test$`", false},
 		{`oneOf "a" "b"`, false},
+		{"anyTimestamp", false},
+		{`anyTimestamp "2006-01-02"`, false},
+		{"anyTimestamp 2006", true},
+		{"not anyString", false},
+		{"not not anyInt", false},
+		{"not ignore", true},
+		{"not unknown", true},
+		{"allOf (anyString) (regex `^a`)", false},
+		{"allOf (not oneOf \"a\") (anyString)", false},
+		{"allOf ()", true},
+		{"empty", false},
+		{"numberBetween 0 100", false},
+		{"numberBetween -1.5 2.5", false},
+		{"numberBetween 10 1", true},
+		{"numberBetween 1", true},
+		{"numberBetween a b", true},
+		{"allOf (anyString", true},
+		{"allOf anyString", true},
+		{`hasClass "active"`, false},
+		{`hasClass "btn" "active"`, false},
+		{"hasClass active", true},
+		{`hasClass "a b"`, true},
+		{"anySemver", false},
+		{`semverRange ">=1.2.0 <2.0.0"`, false},
+		{`semverRange "^1.2.0 || ~2.1.0"`, false},
+		{`semverRange ">=1.2"`, true},
+		{"semverRange >=1.2.0", true},
+		{"anyDuration", false},
+		{"nonEmptyString", false},
+		{"anyBoolish", false},
+		{"regexi `^abc$`", false},
+		{`regexi "^abc$"`, false},
+		{"regexi abc", true},
+		{"anyGoDuration", false},
+		{"anyJSON", false},
+		{`typeOneOf "string" "number"`, false},
+		{`typeOneOf "null"`, false},
+		{`typeOneOf "int"`, true},
+		{"typeOneOf string", true},
+		{`capture "id"`, false},
+		{`ref "id"`, false},
+		{"capture id", true},
+		{`ref "a" "b"`, true},
+		{"type int", false},
+		{"type object", false},
+		{"type integer", true},
+		{`type "int"`, true},
 		{"unknown", true},
 	}
 
@@ -375,6 +549,25 @@ func TestMatchers(t *testing.T) {
 		}
 	})
 
+	t.Run("NonEmptyString", func(t *testing.T) {
+		// GIVEN: a NonEmptyString matcher
+		m := testastic.NonEmptyString()
+
+		// WHEN: matching against a populated string
+		// THEN: it matches
+		if !m.Match("Alice") {
+			t.Error("expected to match non-empty string")
+		}
+
+		// WHEN: matching against empty, whitespace-only and non-string values
+		// THEN: it does not match
+		for _, v := range []any{"", "  \t", 42, nil} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %v", v)
+			}
+		}
+	})
+
 	t.Run("AnyInt", func(t *testing.T) {
 		// GIVEN: an AnyInt matcher
 		m := testastic.AnyInt()
@@ -432,6 +625,27 @@ func TestMatchers(t *testing.T) {
 		}
 	})
 
+	t.Run("AnyBoolish", func(t *testing.T) {
+		// GIVEN: an AnyBoolish matcher
+		m := testastic.AnyBoolish()
+
+		// WHEN: matching against booleans and boolean strings
+		// THEN: it matches
+		for _, v := range []any{true, false, "true", "FALSE", "1", "0"} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %v", v)
+			}
+		}
+
+		// WHEN: matching against other strings and numbers
+		// THEN: it does not match
+		for _, v := range []any{"yes", "", "2", float64(1)} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %v", v)
+			}
+		}
+	})
+
 	t.Run("AnyValue", func(t *testing.T) {
 		// GIVEN: an AnyValue matcher
 		m := testastic.AnyValue()
@@ -471,6 +685,235 @@ func TestMatchers(t *testing.T) {
 		}
 	})
 
+	t.Run("RegexI", func(t *testing.T) {
+		// GIVEN: a case-insensitive regex matcher parsed from a template expression
+		m, err := testastic.ParseMatcher("regexi `^[a-z]+$`")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// WHEN: matching against upper- and mixed-case strings
+		// THEN: case is ignored
+		if !m.Match("ABC") || !m.Match("aBc") {
+			t.Error("expected to match regardless of case")
+		}
+
+		// THEN: String keeps the flag so the expression round-trips
+		if m.String() != "{{regexi `^[a-z]+$`}}" {
+			t.Errorf("unexpected String(): %s", m.String())
+		}
+	})
+
+	t.Run("AnyTimestamp", func(t *testing.T) {
+		// GIVEN: an AnyTimestamp matcher
+		m := testastic.AnyTimestamp()
+
+		// WHEN: matching against RFC3339 timestamps
+		// THEN: it matches
+		if !m.Match("2024-01-15T10:30:00Z") {
+			t.Error("expected to match RFC3339 timestamp")
+		}
+
+		if !m.Match("2024-01-15T10:30:00.123+02:00") {
+			t.Error("expected to match RFC3339 timestamp with fraction and offset")
+		}
+
+		// WHEN: matching against malformed dates and non-strings
+		// THEN: it does not match
+		if m.Match("2024-13-45T99:00:00Z") {
+			t.Error("expected not to match malformed timestamp")
+		}
+
+		if m.Match(1705314600) {
+			t.Error("expected not to match number")
+		}
+	})
+
+	t.Run("AnyTimestampLayout", func(t *testing.T) {
+		// GIVEN: an AnyTimestampLayout matcher for date-only values
+		m := testastic.AnyTimestampLayout("2006-01-02")
+
+		// WHEN: matching against a date and a full timestamp
+		// THEN: only the date matches
+		if !m.Match("2024-01-15") {
+			t.Error("expected to match date")
+		}
+
+		if m.Match("2024-01-15T10:30:00Z") {
+			t.Error("expected not to match full timestamp")
+		}
+
+		// THEN: String echoes the configured layout
+		if m.String() != `{{anyTimestamp "2006-01-02"}}` {
+			t.Errorf("unexpected String(): %s", m.String())
+		}
+	})
+
+	t.Run("AnyDuration", func(t *testing.T) {
+		// GIVEN: an AnyDuration matcher
+		m := testastic.AnyDuration()
+
+		// WHEN: matching against ISO-8601 durations
+		// THEN: it matches
+		for _, v := range []string{"PT1H30M", "P3Y6M4DT12H30M5S", "P2W", "PT0.5S", "-P1D"} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %q", v)
+			}
+		}
+
+		// WHEN: matching against malformed durations, Go durations and non-strings
+		// THEN: it does not match
+		for _, v := range []any{"P", "PT", "P1DT", "PT1H30", "1h30m", "P1H", 90} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %v", v)
+			}
+		}
+	})
+
+	t.Run("AnyGoDuration", func(t *testing.T) {
+		// GIVEN: an AnyGoDuration matcher
+		m := testastic.AnyGoDuration()
+
+		// WHEN: matching against Go and ISO-8601 durations
+		// THEN: only the Go duration matches
+		if !m.Match("1h30m") {
+			t.Error("expected to match Go duration")
+		}
+
+		if m.Match("PT1H30M") {
+			t.Error("expected not to match ISO-8601 duration")
+		}
+	})
+
+	t.Run("AnyJSON", func(t *testing.T) {
+		// GIVEN: an AnyJSON matcher
+		m := testastic.AnyJSON()
+
+		// WHEN: matching against strings holding valid JSON
+		// THEN: it matches
+		for _, v := range []string{`{"id": 1}`, `[1, 2]`, `"text"`, `null`} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %q", v)
+			}
+		}
+
+		// WHEN: matching against invalid JSON and non-string values
+		// THEN: it does not match
+		for _, v := range []any{`{"id": 1`, "", "text", map[string]any{"id": 1}} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %v", v)
+			}
+		}
+	})
+
+	t.Run("Not", func(t *testing.T) {
+		// GIVEN: a Not matcher wrapping OneOf
+		m := testastic.Not(testastic.OneOf("a", "b"))
+
+		// WHEN: matching against a wrapped value and another value
+		// THEN: the result is negated
+		if m.Match("a") {
+			t.Error("expected not to match 'a'")
+		}
+
+		if !m.Match("c") {
+			t.Error("expected to match 'c'")
+		}
+
+		// THEN: String renders the negated expression
+		if m.String() != `{{not oneOf "a" "b"}}` {
+			t.Errorf("unexpected String(): %s", m.String())
+		}
+	})
+
+	t.Run("NotNested", func(t *testing.T) {
+		// GIVEN: a doubly negated matcher parsed from a template expression
+		m, err := testastic.ParseMatcher("not not anyString")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// WHEN: matching against a string and a number
+		// THEN: it behaves like the inner matcher
+		if !m.Match("hello") {
+			t.Error("expected to match string")
+		}
+
+		if m.Match(42) {
+			t.Error("expected not to match number")
+		}
+	})
+
+	t.Run("AllOf", func(t *testing.T) {
+		// GIVEN: an AllOf matcher parsed from a template with nested sub-expressions
+		m, err := testastic.ParseMatcher("allOf (regex `^u-`) (oneOf \"u-1\" \"u-2\")")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// WHEN: matching against a value satisfying all constraints
+		// THEN: it matches
+		if !m.Match("u-1") {
+			t.Error("expected to match 'u-1'")
+		}
+
+		// WHEN: matching against a value failing one constraint
+		// THEN: it does not match
+		if m.Match("u-3") {
+			t.Error("expected not to match 'u-3'")
+		}
+
+		if m.String() != "{{allOf (regex `^u-`) (oneOf \"u-1\" \"u-2\")}}" {
+			t.Errorf("unexpected String(): %s", m.String())
+		}
+	})
+
+	t.Run("NumberInRange", func(t *testing.T) {
+		// GIVEN: a NumberInRange matcher
+		m := testastic.NumberInRange(0, 100)
+
+		// WHEN: matching against values at and within the bounds
+		// THEN: it matches (bounds are inclusive)
+		for _, v := range []any{float64(0), float64(100), 42, int64(7), float32(99.5)} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %v", v)
+			}
+		}
+
+		// WHEN: matching against out-of-range and non-numeric values
+		// THEN: it does not match
+		for _, v := range []any{-0.1, 100.5, "50", nil} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %v", v)
+			}
+		}
+
+		if m.String() != "{{numberBetween 0 100}}" {
+			t.Errorf("unexpected String(): %s", m.String())
+		}
+	})
+
+	t.Run("EmptyValue", func(t *testing.T) {
+		// GIVEN: an EmptyValue matcher
+		m := testastic.EmptyValue()
+
+		// WHEN: matching against empty string, array, and object
+		// THEN: it matches
+		for _, v := range []any{"", []any{}, map[string]any{}, []string{}} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %#v", v)
+			}
+		}
+
+		// WHEN: matching against non-empty values, null, and scalars
+		// THEN: it does not match
+		for _, v := range []any{"x", []any{1}, map[string]any{"a": 1}, nil, float64(0), false} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %#v", v)
+			}
+		}
+	})
+
 	t.Run("OneOf", func(t *testing.T) {
 		// GIVEN: a OneOf matcher with allowed values
 		m := testastic.OneOf("a", "b", "c")
@@ -487,6 +930,137 @@ func TestMatchers(t *testing.T) {
 			t.Error("expected not to match 'd'")
 		}
 	})
+
+	t.Run("StartsWithAndEndsWith", func(t *testing.T) {
+		// GIVEN: prefix and suffix matchers
+		prefix := testastic.StartsWith("https://cdn.example.com/")
+		suffix := testastic.EndsWith(".png")
+
+		// WHEN: matching against strings with the affixes
+		// THEN: they match
+		if !prefix.Match("https://cdn.example.com/a.png") || !suffix.Match("https://cdn.example.com/a.png") {
+			t.Error("expected to match URL with prefix and suffix")
+		}
+
+		// WHEN: matching against other strings and non-strings
+		// THEN: they do not match
+		if prefix.Match("http://cdn.example.com/a.png") || suffix.Match("a.jpg") || suffix.Match(42) {
+			t.Error("expected not to match")
+		}
+	})
+
+	t.Run("Substring", func(t *testing.T) {
+		// GIVEN: a Substring matcher
+		m := testastic.Substring("error")
+
+		// WHEN: matching against strings with and without the substring, and a non-string
+		// THEN: only the string containing it matches
+		if !m.Match("an error occurred") {
+			t.Error("expected to match string containing the substring")
+		}
+
+		if m.Match("all good") || m.Match(42) {
+			t.Error("expected not to match")
+		}
+	})
+
+	t.Run("Present", func(t *testing.T) {
+		// GIVEN: a Present matcher
+		m := testastic.Present()
+
+		// WHEN: matching against non-null values of any type
+		// THEN: they match
+		for _, v := range []any{"", float64(0), false, []any{}, map[string]any{}} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %#v", v)
+			}
+		}
+
+		// WHEN: matching against null
+		// THEN: it does not match
+		if m.Match(nil) {
+			t.Error("expected not to match null")
+		}
+	})
+
+	t.Run("TypeOneOf", func(t *testing.T) {
+		// GIVEN: a TypeOneOf matcher for strings and numbers
+		m := testastic.TypeOneOf("string", "number")
+
+		// WHEN: matching against values of the allowed types
+		// THEN: they match
+		for _, v := range []any{"42", float64(42)} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %#v", v)
+			}
+		}
+
+		// WHEN: matching against values of other types
+		// THEN: they do not match
+		for _, v := range []any{true, nil, []any{}, map[string]any{}} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %#v", v)
+			}
+		}
+	})
+
+	t.Run("AnySemver", func(t *testing.T) {
+		// GIVEN: an AnySemver matcher
+		m := testastic.AnySemver()
+
+		// WHEN: matching against valid versions
+		// THEN: they match
+		for _, v := range []string{"0.0.0", "1.2.3", "1.0.0-alpha.1", "1.0.0+build.5", "1.0.0-rc.1+sha.abc"} {
+			if !m.Match(v) {
+				t.Errorf("expected to match %q", v)
+			}
+		}
+
+		// WHEN: matching against malformed versions
+		// THEN: they do not match
+		for _, v := range []any{"1.2", "v1.2.3", "01.2.3", "1.2.3-01", "1.2.3-", "1.2.3+", 1.2} {
+			if m.Match(v) {
+				t.Errorf("expected not to match %v", v)
+			}
+		}
+	})
+
+	t.Run("SemverRange", func(t *testing.T) {
+		tests := []struct {
+			constraint string
+			version    string
+			want       bool
+		}{
+			{">=1.2.0", "1.10.0", true},
+			{">=1.2.0", "1.2.0-rc.1", false},
+			{">=1.2.0 <2.0.0", "2.0.0", false},
+			{">= 1.2.0, < 2.0.0", "1.9.9", true},
+			{"^1.2.0", "1.99.0", true},
+			{"^1.2.0", "2.0.0-rc.1", false},
+			{"^0.2.3", "0.3.0", false},
+			{"~1.2.0", "1.2.9", true},
+			{"~1.2.0", "1.3.0", false},
+			{"1.2.3", "1.2.3+build", true},
+			{"<1.0.0 || >=3.0.0", "3.1.0", true},
+			{"<1.0.0 || >=3.0.0", "2.0.0", false},
+			{"!=1.0.0", "1.0.0", false},
+			{"<1.0.0-alpha.1", "1.0.0-alpha", true},
+			{"<1.0.0-alpha.beta", "1.0.0-alpha.1", true},
+			{">1.0.0-rc.2", "1.0.0-rc.10", true},
+		}
+
+		for _, tt := range tests {
+			// GIVEN: a SemverRange matcher
+			m, err := testastic.SemverRange(tt.constraint)
+			testastic.NoError(t, err)
+
+			// WHEN: matching a version
+			// THEN: membership follows semver precedence
+			if got := m.Match(tt.version); got != tt.want {
+				t.Errorf("SemverRange(%q).Match(%q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+			}
+		}
+	})
 }
 
 func TestFormatDiff(t *testing.T) {
@@ -516,47 +1090,2042 @@ func TestFormatDiff(t *testing.T) {
 	if !strings.Contains(output, "(missing)") {
 		t.Error("expected output to contain (missing)")
 	}
+
+	// THEN: a summary by type precedes the details
+	testastic.HasPrefix(t, output, "JSON mismatch at 3 paths:\n  1 changed, 1 added, 1 removed\n")
 }
 
-// writeTestFile writes content to a file, failing the test on error.
-func writeTestFile(t *testing.T, path, content string) {
-	t.Helper()
+// uuidPathMatcher is a PathMatcher that reports the path in its failure reason.
+type uuidPathMatcher struct{}
 
-	err := os.WriteFile(path, []byte(content), 0o600)
-	if err != nil {
-		t.Fatal(err)
+var _ testastic.PathMatcher = uuidPathMatcher{}
+
+func (uuidPathMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+
+	return ok && len(s) == 36
+}
+
+func (uuidPathMatcher) String() string { return "{{uuid}}" }
+
+func (m uuidPathMatcher) MatchAt(path string, actual any) (bool, string) {
+	if m.Match(actual) {
+		return true, ""
 	}
+
+	return false, path + " must be a UUID"
 }
 
-// mockT is a mock testing.TB for capturing test failures.
-type mockT struct {
-	testing.TB
-	failed bool
-	output string
+func TestPathMatcher_MatchAt(t *testing.T) {
+	// GIVEN: a path-aware matcher
+	var m testastic.Matcher = uuidPathMatcher{}
+
+	// WHEN: matching through the PathMatcher interface
+	pm, ok := m.(testastic.PathMatcher)
+	if !ok {
+		t.Fatal("expected matcher to implement PathMatcher")
+	}
+
+	matched, reason := pm.MatchAt("$.user.id", "abc")
+
+	// THEN: the failure reason includes the path
+	if matched || reason != "$.user.id must be a UUID" {
+		t.Errorf("expected path-aware failure, got matched=%v reason=%q", matched, reason)
+	}
 }
 
-func (m *mockT) Helper() {}
+func TestFormatDiff_Reason(t *testing.T) {
+	// GIVEN: a matcher failure with a custom reason
+	diffs := []testastic.Difference{
+		{Path: "$.id", Expected: "{{uuid}}", Actual: "abc", Type: testastic.DiffMatcherFailed, Reason: "$.id must be a UUID"},
+	}
 
-func (m *mockT) Fatalf(format string, args ...any) {
-	m.failed = true
-	m.output = strings.TrimSpace(strings.ReplaceAll(format, "%v", ""))
+	// WHEN: formatting the diff
+	output := testastic.FormatDiff(diffs)
 
-	for _, arg := range args {
-		if s, ok := arg.(string); ok {
-			m.output += " " + s
-		}
+	// THEN: the reason is included
+	if !strings.Contains(output, "reason:   $.id must be a UUID") {
+		t.Errorf("expected output to contain reason, got:\n%s", output)
 	}
 }
 
-func (m *mockT) Errorf(format string, args ...any) {
-	m.failed = true
-	m.output = strings.TrimSpace(strings.ReplaceAll(format, "%v", ""))
+func TestCompareJSON(t *testing.T) {
+	// GIVEN: expected JSON with a matcher and non-matching actual JSON
+	expected := []byte(`{"id": "{{anyString}}", "name": "Alice", "age": 30}`)
+	actual := []byte(`{"id": "abc", "name": "Bob", "age": 30}`)
 
-	for _, arg := range args {
-		if s, ok := arg.(string); ok {
-			m.output += " " + s
-		}
+	// WHEN: comparing without a testing.TB
+	diffs, err := testastic.CompareJSON(expected, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: only the differing field is reported
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 difference, got %d: %v", len(diffs), diffs)
+	}
+
+	if diffs[0].Path != "$.name" {
+		t.Errorf("expected difference at $.name, got %s", diffs[0].Path)
 	}
 }
 
-func (m *mockT) Logf(format string, args ...any) {}
+func TestCompareJSON_InvalidActual(t *testing.T) {
+	// GIVEN: valid expected JSON and invalid actual JSON
+	// WHEN: comparing
+	_, err := testastic.CompareJSON([]byte(`{}`), []byte(`{invalid`))
+
+	// THEN: an error is returned
+	if err == nil {
+		t.Error("expected error for invalid actual JSON")
+	}
+}
+
+func TestCompareJSONWithStats(t *testing.T) {
+	// GIVEN: expected and actual JSON with nested changes, additions, and removals
+	expected := []byte(`{"user": {"name": "Alice", "role": "admin"}, "active": true}`)
+	actual := []byte(`{"user": {"name": "Bob", "role": "admin", "tags": []}, "active": false}`)
+
+	// WHEN: comparing with stats
+	diffs, stats, err := testastic.CompareJSONWithStats(expected, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the stats summarize the differences
+	if stats.Total != len(diffs) || stats.Total != 3 {
+		t.Errorf("expected 3 total differences, got %d", stats.Total)
+	}
+
+	if stats.ByType[testastic.DiffChanged] != 2 {
+		t.Errorf("expected 2 changed, got %d", stats.ByType[testastic.DiffChanged])
+	}
+
+	if stats.ByType[testastic.DiffAdded] != 1 {
+		t.Errorf("expected 1 added, got %d", stats.ByType[testastic.DiffAdded])
+	}
+
+	if stats.MaxDepth != 2 {
+		t.Errorf("expected max depth 2, got %d", stats.MaxDepth)
+	}
+}
+
+func TestCompareJSON_StrictNumberTypes(t *testing.T) {
+	// GIVEN: an integer in the expected JSON and a decimal in the actual JSON
+	expected := []byte(`{"count": 1, "ratio": 0.5}`)
+	actual := []byte(`{"count": 1.0, "ratio": 0.5}`)
+
+	// WHEN: comparing with and without StrictNumberTypes
+	lenient, err := testastic.CompareJSON(expected, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strict, err := testastic.CompareJSON(expected, actual, testastic.StrictNumberTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: only the strict comparison reports a type mismatch
+	if len(lenient) != 0 {
+		t.Errorf("expected no differences without StrictNumberTypes, got %v", lenient)
+	}
+
+	if len(strict) != 1 {
+		t.Fatalf("expected 1 difference, got %d: %v", len(strict), strict)
+	}
+
+	if strict[0].Path != "$.count" || strict[0].Type != testastic.DiffTypeMismatch {
+		t.Errorf("expected type mismatch at $.count, got %v", strict[0])
+	}
+}
+
+func TestCompareJSON_UseJSONNumber(t *testing.T) {
+	// GIVEN: large integer IDs that differ only beyond float64 precision
+	expected := []byte(`{"id": 9007199254740993, "count": 1}`)
+	actual := []byte(`{"id": 9007199254740992, "count": 1.0}`)
+
+	// WHEN: comparing with and without UseJSONNumber
+	lossy, err := testastic.CompareJSON(expected, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exact, err := testastic.CompareJSON(expected, actual, testastic.UseJSONNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: only the exact comparison detects the ID change, and 1 still equals 1.0
+	if len(lossy) != 0 {
+		t.Errorf("expected float64 comparison to round the IDs together, got %v", lossy)
+	}
+
+	if len(exact) != 1 || exact[0].Path != "$.id" || exact[0].Type != testastic.DiffChanged {
+		t.Errorf("expected 1 changed difference at $.id, got %v", exact)
+	}
+}
+
+func TestCompareJSON_ReportAddedLeaves(t *testing.T) {
+	// GIVEN: actual JSON with a nested object that is not in expected
+	expected := []byte(`{"name": "Alice", "tags": ["a"]}`)
+	actual := []byte(`{"name": "Alice", "tags": ["a", "b"], "meta": {"plan": {"tier": "pro", "seats": 5}, "flags": []}}`)
+
+	// WHEN: comparing with and without ReportAddedLeaves
+	collapsed, err := testastic.CompareJSON(expected, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaves, err := testastic.CompareJSON(expected, actual, testastic.ReportAddedLeaves())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: without the option the subtree is one difference, with it each leaf is reported
+	if len(collapsed) != 2 {
+		t.Errorf("expected 2 differences without ReportAddedLeaves, got %v", collapsed)
+	}
+
+	var paths []string
+	for _, d := range leaves {
+		if d.Type != testastic.DiffAdded {
+			t.Errorf("expected only added differences, got %v", d)
+		}
+
+		paths = append(paths, d.Path)
+	}
+
+	slices.Sort(paths)
+	testastic.SliceEqual(t, []string{"$.meta.flags", "$.meta.plan.seats", "$.meta.plan.tier", "$.tags[1]"}, paths)
+}
+
+func TestCompareJSON_StrictNumberTypesValueChange(t *testing.T) {
+	// GIVEN: numbers with the same representation but different values
+	expected := []byte(`{"count": 1, "items": [{"id": "{{anyInt}}"}]}`)
+	actual := []byte(`{"count": 2, "items": [{"id": 7}]}`)
+
+	// WHEN: comparing with StrictNumberTypes
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.StrictNumberTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the value change is reported and matchers still apply
+	if len(diffs) != 1 || diffs[0].Type != testastic.DiffChanged {
+		t.Fatalf("expected 1 changed difference, got %v", diffs)
+	}
+}
+
+func TestCompareJSON_Epsilon(t *testing.T) {
+	// GIVEN: floating point values that drifted slightly during serialization
+	expected := []byte(`{"price": 19.99, "tax": 1.5}`)
+	actual := []byte(`{"price": 19.989999999, "tax": 1.6}`)
+
+	// WHEN: comparing with a global tolerance
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.Epsilon(0.0001))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: only the value outside the tolerance is reported
+	if len(diffs) != 1 || diffs[0].Path != "$.tax" {
+		t.Fatalf("expected 1 difference at $.tax, got %v", diffs)
+	}
+}
+
+func TestCompareJSON_EpsilonAt(t *testing.T) {
+	// GIVEN: numeric drift both inside and outside a scoped path
+	expected := []byte(`{"stats": {"mean": 2.5, "items": [1.0]}, "total": 10}`)
+	actual := []byte(`{"stats": {"mean": 2.51, "items": [1.02]}, "total": 10.01}`)
+
+	// WHEN: comparing with a tolerance scoped to $.stats
+	diffs, err := testastic.CompareJSON(expected, actual,
+		testastic.Epsilon(0.001),
+		testastic.EpsilonAt("$.stats", 0.05),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: values under $.stats use the scoped tolerance and others the global one
+	if len(diffs) != 1 || diffs[0].Path != "$.total" {
+		t.Fatalf("expected 1 difference at $.total, got %v", diffs)
+	}
+}
+
+func TestAssertJSON_WithAnyTimestampMatcher(t *testing.T) {
+	// GIVEN: an expected JSON file with anyTimestamp matchers
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "timestamp.expected.json")
+
+	expected := `{
+  "created_at": "{{anyTimestamp}}",
+  "birthday": "{{anyTimestamp \"2006-01-02\"}}"
+}`
+	writeTestFile(t, expectedFile, expected)
+
+	// WHEN: asserting with valid timestamps
+	actual := `{"created_at": "2024-01-15T10:30:00Z", "birthday": "1990-05-20"}`
+
+	// THEN: the test passes
+	testastic.AssertJSON(t, expectedFile, actual)
+
+	// WHEN: asserting with a malformed timestamp
+	mt := &mockT{}
+	testastic.AssertJSON(mt, expectedFile, `{"created_at": "yesterday", "birthday": "1990-05-20"}`)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected test to fail for malformed timestamp")
+	}
+}
+
+func TestAssertJSON_WithNotMatcher(t *testing.T) {
+	// GIVEN: an expected JSON file with a negated regex matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "not.expected.json")
+
+	expected := "{\"name\": \"{{not regex `^tmp-`}}\"}"
+	writeTestFile(t, expectedFile, expected)
+
+	// WHEN: asserting with a value not matching the regex
+	// THEN: the test passes
+	testastic.AssertJSON(t, expectedFile, `{"name": "prod-1"}`)
+
+	// WHEN: asserting with a value matching the regex
+	mt := &mockT{}
+	testastic.AssertJSON(mt, expectedFile, `{"name": "tmp-1"}`)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected test to fail for negated match")
+	}
+}
+
+func TestCompareJSON_AllOfReportsFailingSubMatcher(t *testing.T) {
+	// GIVEN: an expected template with an allOf matcher
+	expected := []byte("{\"id\": \"{{allOf (regex `^u-`) (oneOf \\\"u-1\\\" \\\"u-2\\\")}}\"}")
+
+	// WHEN: comparing with a value that fails only the oneOf constraint
+	diffs, err := testastic.CompareJSON(expected, []byte(`{"id": "u-3"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the difference names the failing sub-matcher
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 difference, got %d", len(diffs))
+	}
+
+	desc, _ := diffs[0].Expected.(string)
+	if !strings.Contains(desc, `failed: {{oneOf "u-1" "u-2"}}`) {
+		t.Errorf("expected failing sub-matcher in description, got: %v", diffs[0].Expected)
+	}
+}
+
+func TestAssertJSON_WithNumberBetweenMatcher(t *testing.T) {
+	// GIVEN: an expected JSON file with a numberBetween matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "range.expected.json")
+
+	writeTestFile(t, expectedFile, `{"score": "{{numberBetween 0 100}}"}`)
+
+	// WHEN: asserting with an in-range value
+	// THEN: the test passes
+	testastic.AssertJSON(t, expectedFile, `{"score": 87.5}`)
+
+	// WHEN: asserting with an out-of-range value
+	mt := &mockT{}
+	testastic.AssertJSON(mt, expectedFile, `{"score": 120}`)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected test to fail for out-of-range value")
+	}
+}
+
+func TestAssertJSON_WithEmptyMatcher(t *testing.T) {
+	// GIVEN: an expected JSON file with empty matchers
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "empty.expected.json")
+
+	writeTestFile(t, expectedFile, `{"note": "{{empty}}", "tags": "{{empty}}", "meta": "{{empty}}"}`)
+
+	// WHEN: asserting with empty values
+	// THEN: the test passes
+	testastic.AssertJSON(t, expectedFile, `{"note": "", "tags": [], "meta": {}}`)
+
+	// WHEN: asserting with a non-empty array
+	mt := &mockT{}
+	testastic.AssertJSON(mt, expectedFile, `{"note": "", "tags": ["a"], "meta": {}}`)
+
+	// THEN: the test fails
+	if !mt.failed {
+		t.Error("expected test to fail for non-empty array")
+	}
+}
+
+// writeTestFile writes content to a file, failing the test on error.
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// mockT is a mock testing.TB for capturing test failures.
+type mockT struct {
+	testing.TB
+	failed bool
+	output string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Fatalf(format string, args ...any) {
+	m.failed = true
+	m.output = strings.TrimSpace(strings.ReplaceAll(format, "%v", ""))
+
+	for _, arg := range args {
+		if s, ok := arg.(string); ok {
+			m.output += " " + s
+		}
+	}
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.failed = true
+	m.output = strings.TrimSpace(strings.ReplaceAll(format, "%v", ""))
+
+	for _, arg := range args {
+		if s, ok := arg.(string); ok {
+			m.output += " " + s
+		}
+	}
+}
+
+func (m *mockT) Logf(format string, args ...any) {}
+
+func TestAssertJSONEqual(t *testing.T) {
+	// GIVEN: two structs that differ only in an ignored field
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// WHEN: comparing them in memory
+	// THEN: the assertion passes
+	testastic.AssertJSONEqual(t, user{ID: 1, Name: "Alice"}, user{ID: 2, Name: "Alice"}, testastic.IgnoreFields("id"))
+}
+
+func TestAssertJSONEqual_ReasonsSortedByPath(t *testing.T) {
+	// GIVEN: aliased fields whose differences each carry a reason
+	expected := `{"d": 0, "a": 0, "c": 0, "b": 0}`
+	actual := `{"w": 1, "x": 1, "y": 1, "z": 1}`
+	opts := []testastic.Option{
+		testastic.FieldAlias("$.a", "w"), testastic.FieldAlias("$.b", "x"),
+		testastic.FieldAlias("$.c", "y"), testastic.FieldAlias("$.d", "z"),
+	}
+
+	for range 10 {
+		r := &recordingReporter{}
+
+		// WHEN: comparing them in memory
+		testastic.AssertJSONEqual(r, expected, actual, opts...)
+
+		// THEN: the reasons are listed in path order on every run
+		testastic.Len(t, r.errors, 1)
+		assertInOrder(t, r.errors[0], "$.a\n    reason:", "$.b\n    reason:", "$.c\n    reason:", "$.d\n    reason:")
+	}
+}
+
+func TestAssertJSONEqual_Mismatch(t *testing.T) {
+	// GIVEN: two JSON strings with a differing field
+	r := &recordingReporter{}
+
+	// WHEN: comparing them in memory
+	testastic.AssertJSONEqual(r, `{"name": "Alice"}`, `{"name": "Bob"}`)
+
+	// THEN: the difference is reported
+	if len(r.errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(r.errors), r.errors)
+	}
+
+	if !strings.Contains(r.errors[0], "AssertJSONEqual") {
+		t.Errorf("expected error to mention AssertJSONEqual, got: %s", r.errors[0])
+	}
+}
+
+func TestCompareJSON_DiscriminatedUnionAt(t *testing.T) {
+	// GIVEN: shape files for each discriminator value
+	dir := t.TempDir()
+	userShape := filepath.Join(dir, "user.expected.json")
+	orderShape := filepath.Join(dir, "order.expected.json")
+
+	err := os.WriteFile(userShape, []byte(`{"name": "{{anyString}}"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(orderShape, []byte(`{"total": "{{anyFloat}}"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union := testastic.DiscriminatedUnionAt("$.data", "type", map[string]string{
+		"user":  userShape,
+		"order": orderShape,
+	})
+	expected := []byte(`{"type": "{{anyString}}", "data": "{{anyValue}}"}`)
+
+	tests := []struct {
+		name      string
+		actual    string
+		wantPaths []string
+	}{
+		{"user shape", `{"type": "user", "data": {"name": "Alice"}}`, nil},
+		{"order shape", `{"type": "order", "data": {"total": 9.5}}`, nil},
+		{"shape mismatch", `{"type": "order", "data": {"name": "Alice"}}`, []string{"$.data.name", "$.data.total"}},
+		{"unknown discriminator", `{"type": "refund", "data": {}}`, []string{"$.data"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN: comparing with the discriminated union option
+			diffs, err := testastic.CompareJSON(expected, []byte(tt.actual), union)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// THEN: the data is compared against the selected shape
+			if len(diffs) != len(tt.wantPaths) {
+				t.Fatalf("expected %d differences, got %v", len(tt.wantPaths), diffs)
+			}
+
+			for i, want := range tt.wantPaths {
+				if diffs[i].Path != want {
+					t.Errorf("expected difference at %s, got %s", want, diffs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareJSON_DiscriminatedUnionUnorderedShape(t *testing.T) {
+	// GIVEN: a shape file with an unordered array
+	shapeFile := filepath.Join(t.TempDir(), "tagged.expected.json")
+
+	err := os.WriteFile(shapeFile, []byte(`{"tags": {"{{unordered}}": [1, 2]}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union := testastic.DiscriminatedUnionAt("$.data", "type", map[string]string{"tagged": shapeFile})
+
+	// WHEN: comparing data whose array is reordered
+	diffs, err := testastic.CompareJSON([]byte(`{"type": "tagged", "data": "{{ignore}}"}`),
+		[]byte(`{"type": "tagged", "data": {"tags": [2, 1]}}`), union)
+
+	// THEN: the array is compared regardless of order
+	testastic.NoError(t, err)
+	testastic.Empty(t, diffs)
+}
+
+func TestCompareJSON_DiscriminatedUnionRejectsExpectedValue(t *testing.T) {
+	// GIVEN: an expected file with a concrete value at the union path
+	shapeFile := filepath.Join(t.TempDir(), "n.expected.json")
+
+	err := os.WriteFile(shapeFile, []byte(`{"n": "{{anyInt}}"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union := testastic.DiscriminatedUnionAt("$.data", "type", map[string]string{"n": shapeFile})
+
+	// WHEN: comparing
+	diffs, err := testastic.CompareJSON([]byte(`{"type": "n", "data": {"n": "this is totally different"}}`),
+		[]byte(`{"type": "n", "data": {"n": 1}}`), union)
+
+	// THEN: the expected value is reported instead of silently ignored
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "$.data", diffs[0].Path)
+	testastic.Contains(t, diffs[0].Reason, "the shape file defines this value")
+}
+
+func TestCompareJSON_DiscriminatedUnionUnknownShapeMessage(t *testing.T) {
+	// GIVEN: a discriminator value without a registered shape
+	union := testastic.DiscriminatedUnionAt("$.data", "type", map[string]string{})
+
+	// WHEN: comparing
+	diffs, err := testastic.CompareJSON([]byte(`{"type": "refund", "data": "{{ignore}}"}`),
+		[]byte(`{"type": "refund", "data": {}}`), union)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the failure names the unregistered discriminator value
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 difference, got %v", diffs)
+	}
+
+	if !strings.Contains(testastic.FormatDiff(diffs), "registered shape for type refund") {
+		t.Errorf("expected clear error message, got:\n%s", testastic.FormatDiff(diffs))
+	}
+}
+
+func TestAssertJSONString(t *testing.T) {
+	// GIVEN: an inline expected JSON string with matchers
+	expected := `{"id": "{{anyString}}", "name": "Alice", "age": "{{anyInt}}"}`
+
+	// WHEN: comparing against matching actual JSON
+	// THEN: the assertion passes
+	testastic.AssertJSONString(t, expected, `{"id": "abc-123", "name": "Alice", "age": 30}`)
+}
+
+func TestAssertJSONString_ReasonsSortedByPath(t *testing.T) {
+	// GIVEN: several matcher failures that each carry a reason
+	expected := `{"d": "{{anyInt}}", "a": "{{anyInt}}", "c": "{{anyInt}}", "b": "{{anyInt}}"}`
+	actual := `{"a": 1.5, "b": 2.5, "c": 3.5, "d": 4.5}`
+
+	for range 10 {
+		r := &recordingReporter{}
+
+		// WHEN: asserting
+		testastic.AssertJSONString(r, expected, actual)
+
+		// THEN: the reasons are listed in path order on every run
+		testastic.Len(t, r.errors, 1)
+		assertInOrder(t, r.errors[0], "$.a\n    reason:", "$.b\n    reason:", "$.c\n    reason:", "$.d\n    reason:")
+	}
+}
+
+// assertInOrder fails the test unless each of parts occurs in s after the previous one.
+func assertInOrder(t *testing.T, s string, parts ...string) {
+	t.Helper()
+
+	rest := s
+
+	for _, part := range parts {
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			t.Fatalf("expected %q after the preceding parts in:\n%s", part, s)
+		}
+
+		rest = rest[idx+len(part):]
+	}
+}
+
+func TestAssertJSONString_IgnoresUpdate(t *testing.T) {
+	// GIVEN: an inline expected string and mismatching actual JSON
+	r := &recordingReporter{}
+
+	// WHEN: asserting in update mode
+	testastic.AssertJSONString(r, `{"name": "Alice"}`, `{"name": "Bob"}`, testastic.Update())
+
+	// THEN: the mismatch is still reported instead of writing a file
+	if len(r.errors) != 1 || len(r.fatals) != 0 {
+		t.Fatalf("expected 1 error and no fatals, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+
+	if !strings.Contains(r.errors[0], "AssertJSONString") {
+		t.Errorf("expected error to mention AssertJSONString, got: %s", r.errors[0])
+	}
+}
+
+func TestAssertJSON_UnorderedWrapper(t *testing.T) {
+	// GIVEN: an expected file marking one array as unordered
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "unordered.expected.json")
+
+	expected := `{
+  "tags": {"{{unordered}}": ["a", "b", "c"]},
+  "steps": [1, 2, 3]
+}`
+
+	err := os.WriteFile(expectedFile, []byte(expected), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: the marked array is reordered
+	// THEN: the assertion passes
+	testastic.AssertJSON(t, expectedFile, `{"tags": ["c", "a", "b"], "steps": [1, 2, 3]}`)
+}
+
+func TestCompareJSON_UnorderedWrapperScoped(t *testing.T) {
+	// GIVEN: an expected document with one unordered and one ordered array
+	expected := []byte(`{"tags": {"{{unordered}}": ["a", "b"]}, "steps": [1, 2]}`)
+
+	// WHEN: both arrays are reordered
+	diffs, err := testastic.CompareJSON(expected, []byte(`{"tags": ["b", "a"], "steps": [2, 1]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: only the ordered array reports differences
+	for _, d := range diffs {
+		if !strings.HasPrefix(d.Path, "$.steps") {
+			t.Errorf("unexpected difference at %s", d.Path)
+		}
+	}
+
+	if len(diffs) == 0 {
+		t.Error("expected differences for the ordered array")
+	}
+}
+
+func TestParseExpectedString_UnorderedWrapperInvalid(t *testing.T) {
+	// GIVEN: an unordered wrapper around a non-array value
+	// WHEN: parsing
+	_, err := testastic.ParseExpectedString(`{"tags": {"{{unordered}}": "a"}}`)
+
+	// THEN: a syntax error is returned
+	if !errors.Is(err, testastic.ErrInvalidUnorderedSyntax) {
+		t.Errorf("expected ErrInvalidUnorderedSyntax, got %v", err)
+	}
+}
+
+func TestAssertJSON_UpdatePreservesUnorderedWrapper(t *testing.T) {
+	// GIVEN: an expected file with an unordered array
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "unordered.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"tags": {"{{unordered}}": ["a"]}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: updating with a changed array
+	testastic.AssertJSON(t, expectedFile, `{"tags": ["a", "b"]}`, testastic.Update())
+
+	// THEN: the wrapper is kept in the updated file
+	content, err := os.ReadFile(expectedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(content), "{{unordered}}") {
+		t.Errorf("expected unordered wrapper to be preserved, got:\n%s", content)
+	}
+}
+
+func TestAssertJSON_UpdatePreservesKeyOrder(t *testing.T) {
+	// GIVEN: a hand-authored expected file with keys in non-alphabetical order
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "order.expected.json")
+	original := `{
+  "name": "Alice",
+  "id": "{{anyString}}",
+  "roles": [
+    {
+      "scope": "admin",
+      "level": 1
+    }
+  ],
+  "address": {
+    "street": "Main St",
+    "city": "Berlin"
+  }
+}
+`
+
+	err := os.WriteFile(expectedFile, []byte(original), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: updating with a changed value and a new key
+	actual := `{"address": {"city": "Hamburg", "street": "Main St"}, "id": "u-1", "name": "Alice",
+		"roles": [{"level": 1, "scope": "admin"}], "email": "alice@example.com"}`
+	testastic.AssertJSON(t, expectedFile, actual, testastic.Update())
+
+	// THEN: only the changed value differs, keys keep their order and the new key is appended
+	content, err := os.ReadFile(expectedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Replace(original, `"Berlin"
+  }`, `"Hamburg"
+  },
+  "email": "alice@example.com"`, 1)
+	testastic.Equal(t, want, string(content))
+}
+
+func TestAssertJSON_UpdateKeepsNumbersAsWritten(t *testing.T) {
+	// GIVEN: a hand-authored expected file with a large integer and a trailing zero
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "numbers.expected.json")
+	original := `{
+  "status": "pending",
+  "id": 12345678901234567890,
+  "price": 1.50
+}
+`
+
+	err := os.WriteFile(expectedFile, []byte(original), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: updating with only the status changed
+	actual := `{"status": "active", "id": 12345678901234567890, "price": 1.50}`
+	testastic.AssertJSON(t, expectedFile, actual, testastic.Update())
+
+	// THEN: the unchanged numbers are written byte for byte
+	content, err := os.ReadFile(expectedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testastic.Equal(t, strings.Replace(original, "pending", "active", 1), string(content))
+}
+
+func TestCompareJSON_AllowExtraFields(t *testing.T) {
+	// GIVEN: an expected subset of a larger actual document
+	expected := []byte(`{"user": {"name": "Alice"}, "items": [1, 2]}`)
+
+	tests := []struct {
+		name      string
+		actual    string
+		wantPaths []string
+	}{
+		{"extra fields tolerated", `{"user": {"name": "Alice", "id": 1}, "items": [1, 2], "meta": {}}`, nil},
+		{"missing field fails", `{"user": {"id": 1}, "items": [1, 2]}`, []string{"$.user.name"}},
+		{"extra array element fails", `{"user": {"name": "Alice"}, "items": [1, 2, 3]}`, []string{"$.items[2]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN: comparing with AllowExtraFields
+			diffs, err := testastic.CompareJSON(expected, []byte(tt.actual), testastic.AllowExtraFields())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// THEN: only non-extra-field differences are reported
+			if len(diffs) != len(tt.wantPaths) {
+				t.Fatalf("expected %d differences, got %v", len(tt.wantPaths), diffs)
+			}
+
+			for i, want := range tt.wantPaths {
+				if diffs[i].Path != want {
+					t.Errorf("expected difference at %s, got %s", want, diffs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestAssertForm(t *testing.T) {
+	// GIVEN: an expected file with matchers on form values
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "form.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"name": ["Alice"], "tags": ["a", "b"], "token": ["{{anyString}}"]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: asserting form-encoded data and url.Values
+	// THEN: both pass
+	testastic.AssertForm(t, expectedFile, "name=Alice&tags=a&tags=b&token=xyz")
+	testastic.AssertForm(t, expectedFile, url.Values{
+		"name":  {"Alice"},
+		"tags":  {"a", "b"},
+		"token": {"abc"},
+	})
+}
+
+func TestAssertForm_Mismatch(t *testing.T) {
+	// GIVEN: an expected form file
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "form.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"name": ["Alice"]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting form data with a different value
+	testastic.AssertForm(r, expectedFile, []byte("name=Bob"))
+
+	// THEN: the mismatch is reported under AssertForm
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "AssertForm") {
+		t.Errorf("expected AssertForm failure, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestMatcherBuilders(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher testastic.Matcher
+		actual  any
+		want    bool
+	}{
+		{"string matches", testastic.String().NonEmpty().MaxLen(5), "abc", true},
+		{"string empty", testastic.String().NonEmpty(), "", false},
+		{"string too long", testastic.String().MaxLen(2), "abc", false},
+		{"string too short", testastic.String().MinLen(4), "abc", false},
+		{"string counts runes", testastic.String().MaxLen(2), "äö", true},
+		{"string wrong type", testastic.String(), float64(1), false},
+		{"number in range", testastic.Number().Between(0, 100), float64(42), true},
+		{"number out of range", testastic.Number().Between(0, 100), float64(101), false},
+		{"number integer", testastic.Number().Integer().Min(1), float64(2), true},
+		{"number not integer", testastic.Number().Integer(), 2.5, false},
+		{"number above max", testastic.Number().Max(10), float64(11), false},
+		{"number wrong type", testastic.Number(), "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN: a matcher built with chained constraints
+			// WHEN: matching the actual value
+			got := tt.matcher.Match(tt.actual)
+
+			// THEN: the result reflects all constraints
+			if got != tt.want {
+				t.Errorf("%s.Match(%v) = %v, want %v", tt.matcher, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherBuilders_String(t *testing.T) {
+	// GIVEN: builder chains
+	// WHEN: rendering them
+	// THEN: the full constraint chain is described
+	if got := testastic.String().NonEmpty().MaxLen(64).String(); got != "{{string nonEmpty maxLen 64}}" {
+		t.Errorf("unexpected string rendering: %s", got)
+	}
+
+	if got := testastic.Number().Integer().Between(0, 100).String(); got != "{{number integer between 0 100}}" {
+		t.Errorf("unexpected number rendering: %s", got)
+	}
+}
+
+func TestCompareJSON_WithMatcherAt(t *testing.T) {
+	// GIVEN: builder matchers attached at paths
+	expected := []byte(`{"name": "placeholder", "age": 0}`)
+	actual := []byte(`{"name": "Alice", "age": 150}`)
+
+	// WHEN: comparing
+	diffs, err := testastic.CompareJSON(expected, actual,
+		testastic.WithMatcherAt("$.name", testastic.String().NonEmpty().MaxLen(64)),
+		testastic.WithMatcherAt("$.age", testastic.Number().Integer().Between(0, 120)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the failing constraint is named in the difference
+	if len(diffs) != 1 || diffs[0].Path != "$.age" {
+		t.Fatalf("expected 1 difference at $.age, got %v", diffs)
+	}
+
+	want := "{{number integer between 0 120}} (failed: between 0 120)"
+	if diffs[0].Expected != want {
+		t.Errorf("expected %q, got %v", want, diffs[0].Expected)
+	}
+}
+
+func TestCompareJSON_WithMatcherAtPathMatcherReason(t *testing.T) {
+	// GIVEN: a path-aware matcher attached at a path
+	// WHEN: the actual value does not match
+	diffs, err := testastic.CompareJSON([]byte(`{"id": ""}`), []byte(`{"id": "abc"}`),
+		testastic.WithMatcherAt("$.id", uuidPathMatcher{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the matcher's reason is included in the difference
+	if len(diffs) != 1 || diffs[0].Reason != "$.id must be a UUID" {
+		t.Fatalf("expected difference with path-aware reason, got %v", diffs)
+	}
+}
+
+func TestAssertJSONEqual_DetectTautology(t *testing.T) {
+	// GIVEN: the same value passed as expected and actual
+	r := &recordingReporter{}
+	value := `{"name": "Alice"}`
+
+	// WHEN: asserting with DetectTautology
+	testastic.AssertJSONEqual(r, value, value, testastic.DetectTautology())
+
+	// THEN: a warning is logged but the assertion does not fail
+	if len(r.errors) != 0 {
+		t.Errorf("expected no errors, got %v", r.errors)
+	}
+
+	if len(r.logs) != 1 || !strings.Contains(r.logs[0], "tautological") {
+		t.Errorf("expected tautology warning, got %v", r.logs)
+	}
+}
+
+func TestAssertJSON_DetectTautologyDifferentBytes(t *testing.T) {
+	// GIVEN: an expected file and a semantically equal but differently formatted actual value
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "user.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(testJSONAliceOnly), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting with DetectTautology
+	testastic.AssertJSON(r, expectedFile, `{"name":"Alice"}`, testastic.DetectTautology())
+
+	// THEN: no warning is logged
+	if len(r.logs) != 0 || len(r.errors) != 0 {
+		t.Errorf("expected no logs or errors, got logs=%v errors=%v", r.logs, r.errors)
+	}
+}
+
+func TestFormatDiffInline_ChangedScalar(t *testing.T) {
+	// GIVEN: documents differing in a single scalar value
+	expected := map[string]any{"name": "Alice Smith", "age": float64(30)}
+	actual := map[string]any{"name": "Alicia Smith", "age": float64(30)}
+
+	// WHEN: formatting the inline diff
+	output := testastic.FormatDiffInline(expected, actual)
+
+	// THEN: the changed line is shown once as removed and once as added
+	if strings.Count(output, `"name": "Alic`) != 2 {
+		t.Errorf("expected removed and added name lines, got:\n%s", output)
+	}
+
+	if strings.Count(output, `"age": 30`) != 1 {
+		t.Errorf("expected unchanged age line once, got:\n%s", output)
+	}
+}
+
+// prefixMatcher matches strings with a prefix, for testing custom matcher registration.
+type prefixMatcher struct {
+	prefix string
+}
+
+func (m prefixMatcher) Match(actual any) bool {
+	s, ok := actual.(string)
+
+	return ok && strings.HasPrefix(s, m.prefix)
+}
+
+func (m prefixMatcher) String() string {
+	return fmt.Sprintf("{{myToken %q}}", m.prefix)
+}
+
+func TestRegisterMatcher(t *testing.T) {
+	// GIVEN: a registered custom matcher
+	t.Cleanup(testastic.ResetMatchers)
+
+	var gotArgs []string
+
+	testastic.RegisterMatcher("myToken", func(args []string) (testastic.Matcher, error) {
+		gotArgs = args
+
+		return prefixMatcher{prefix: args[0]}, nil
+	})
+
+	// WHEN: using it in an expected document
+	diffs, err := testastic.CompareJSON(
+		[]byte(`{"good": "{{myToken \"v2\"}}", "bad": "{{myToken \"v2\"}}"}`),
+		[]byte(`{"good": "v2.abc", "bad": "v1.abc"}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the factory receives the arguments and the matcher is applied
+	if len(gotArgs) != 1 || gotArgs[0] != "v2" {
+		t.Errorf("expected args [v2], got %v", gotArgs)
+	}
+
+	if len(diffs) != 1 || diffs[0].Path != "$.bad" {
+		t.Errorf("expected 1 difference at $.bad, got %v", diffs)
+	}
+}
+
+func TestRegisterMatcher_Args(t *testing.T) {
+	// GIVEN: a registered matcher recording its arguments
+	t.Cleanup(testastic.ResetMatchers)
+
+	var gotArgs []string
+
+	testastic.RegisterMatcher("record", func(args []string) (testastic.Matcher, error) {
+		gotArgs = args
+
+		return testastic.AnyValue(), nil
+	})
+
+	// WHEN: parsing an expression with quoted, backticked, and bare arguments
+	_, err := testastic.ParseMatcher("record \"a b\" `c d` 42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the arguments are split and unquoted
+	want := []string{"a b", "c d", "42"}
+	if strings.Join(gotArgs, "|") != strings.Join(want, "|") {
+		t.Errorf("expected args %v, got %v", want, gotArgs)
+	}
+}
+
+func TestResetMatchers(t *testing.T) {
+	// GIVEN: a registered custom matcher
+	testastic.RegisterMatcher("temp", func([]string) (testastic.Matcher, error) {
+		return testastic.AnyValue(), nil
+	})
+
+	// WHEN: resetting the registry
+	testastic.ResetMatchers()
+
+	// THEN: the matcher is unknown again
+	_, err := testastic.ParseMatcher("temp")
+	if !errors.Is(err, testastic.ErrUnknownMatcher) {
+		t.Errorf("expected ErrUnknownMatcher, got %v", err)
+	}
+}
+
+func TestAssertMatchesSchemaExample(t *testing.T) {
+	// GIVEN: a JSON Schema with examples
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "user.schema.json")
+
+	schema := `{
+  "type": "object",
+  "examples": [
+    {"name": "Alice", "role": "admin"},
+    {"name": "Bob", "role": "viewer"}
+  ]
+}`
+
+	err := os.WriteFile(schemaFile, []byte(schema), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: asserting a value matching the second example with an extra field
+	// THEN: the assertion passes
+	testastic.AssertMatchesSchemaExample(t, schemaFile, `{"name": "Bob", "role": "viewer", "id": 7}`)
+
+	// WHEN: asserting a value matching no example
+	r := &recordingReporter{}
+	testastic.AssertMatchesSchemaExample(r, schemaFile, `{"name": "Carol", "role": "viewer"}`)
+
+	// THEN: the closest example is reported
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "closest is examples[1]") {
+		t.Errorf("expected failure naming the closest example, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertMatchesSchemaExample_NoExamples(t *testing.T) {
+	// GIVEN: a JSON Schema without examples
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "empty.schema.json")
+
+	err := os.WriteFile(schemaFile, []byte(`{"type": "object"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting against it
+	testastic.AssertMatchesSchemaExample(r, schemaFile, `{}`)
+
+	// THEN: a fatal error is reported
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "schema has no examples") {
+		t.Errorf("expected fatal about missing examples, got %v", r.fatals)
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// GIVEN: a base document and a merge patch (RFC 7386 example)
+	base := []byte(`{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"},
+		"tags": ["example", "sample"], "content": "This will be unchanged", "id": 9007199254740993}`)
+	patch := []byte(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890",
+		"author": {"familyName": null}, "tags": ["example"]}`)
+
+	// WHEN: applying the patch
+	merged, err := testastic.ApplyMergePatch(base, patch)
+
+	// THEN: members are replaced, added, and removed per the RFC, and numbers keep their precision
+	testastic.NoError(t, err)
+	testastic.AssertJSONEqual(t, []byte(`{"title": "Hello!", "author": {"givenName": "John"},
+		"tags": ["example"], "content": "This will be unchanged", "phoneNumber": "+01-123-456-7890",
+		"id": 9007199254740993}`), merged)
+	testastic.Contains(t, string(merged), "9007199254740993")
+}
+
+func TestApplyMergePatch_NonObjectPatch(t *testing.T) {
+	// GIVEN: a non-object patch
+	// WHEN: applying it
+	merged, err := testastic.ApplyMergePatch([]byte(`{"a": "b"}`), []byte(`["c"]`))
+
+	// THEN: the patch replaces the base document
+	testastic.NoError(t, err)
+	testastic.Equal(t, `["c"]`, string(merged))
+}
+
+func TestApplyMergePatch_InvalidJSON(t *testing.T) {
+	// GIVEN: an invalid patch
+	// WHEN: applying it
+	_, err := testastic.ApplyMergePatch([]byte(`{}`), []byte(`{`))
+
+	// THEN: an error is returned
+	testastic.ErrorContains(t, err, "merge patch")
+}
+
+func TestCompareJSONValue(t *testing.T) {
+	// GIVEN: an expected Go value mixing concrete values, a time.Time, and matchers
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	expected := map[string]any{
+		"id":      testastic.AnyInt(),
+		"name":    "Alice",
+		"created": created,
+		"roles":   []any{"admin", testastic.AnyString()},
+		"profile": struct {
+			Age int `json:"age"`
+		}{Age: 30},
+	}
+
+	actual := []byte(`{"id": 42, "name": "Alice", "created": "2024-01-02T03:04:05Z",
+		"roles": ["admin", "editor"], "profile": {"age": 30}}`)
+
+	// WHEN: comparing without a template file
+	diffs, err := testastic.CompareJSONValue(expected, actual)
+
+	// THEN: concrete values and matchers are compared directly
+	testastic.NoError(t, err)
+	testastic.Empty(t, diffs)
+}
+
+func TestCompareJSONValue_MatcherStringIsLiteral(t *testing.T) {
+	// GIVEN: a matcher written as a template string instead of a Matcher value
+	expected := map[string]any{"id": "{{anyInt}}"}
+
+	// WHEN: comparing against a number
+	diffs, err := testastic.CompareJSONValue(expected, []byte(`{"id": 42}`))
+
+	// THEN: the string is compared literally and the difference is reported
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+}
+
+func TestAssertJSONValue_Fail(t *testing.T) {
+	// GIVEN: an expected value with a matcher that does not match
+	r := &recordingReporter{}
+
+	// WHEN: asserting
+	testastic.AssertJSONValue(r, map[string]any{"id": testastic.AnyString()}, `{"id": 42}`)
+
+	// THEN: the failure is reported
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "AssertJSONValue") {
+		t.Errorf("expected AssertJSONValue failure, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertJSONValue_ReasonsSortedByPath(t *testing.T) {
+	// GIVEN: several matcher failures that each carry a reason
+	expected := map[string]any{
+		"a": testastic.AnyInt(), "b": testastic.AnyInt(), "c": testastic.AnyInt(), "d": testastic.AnyInt(),
+	}
+	actual := `{"a": 1.5, "b": 2.5, "c": 3.5, "d": 4.5}`
+
+	for range 10 {
+		r := &recordingReporter{}
+
+		// WHEN: asserting
+		testastic.AssertJSONValue(r, expected, actual)
+
+		// THEN: the reasons are listed in path order on every run
+		testastic.Len(t, r.errors, 1)
+		assertInOrder(t, r.errors[0], "$.a\n    reason:", "$.b\n    reason:", "$.c\n    reason:", "$.d\n    reason:")
+	}
+}
+
+func TestCompareJSON_IgnoreFieldsMatching(t *testing.T) {
+	// GIVEN: arrays whose element IDs and nested timestamps differ
+	expected := []byte(`{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}],
+		"meta": {"page": {"fetchedAt": "x"}, "user": {"fetchedAt": "y"}}, "id": 10}`)
+	actual := []byte(`{"items": [{"id": 7, "name": "a"}, {"id": 8, "name": "b"}],
+		"meta": {"page": {"fetchedAt": "z"}, "user": {"fetchedAt": "w"}}, "id": 11}`)
+
+	// WHEN: ignoring fields with wildcard paths
+	diffs, err := testastic.CompareJSON(expected, actual,
+		testastic.IgnoreFieldsMatching("$.items[*].id", "$.meta.*.fetchedAt"))
+
+	// THEN: only the non-matching path is reported
+	testastic.NoError(t, err)
+
+	if len(diffs) != 1 || diffs[0].Path != "$.id" {
+		t.Errorf("expected only $.id to differ, got %v", diffs)
+	}
+}
+
+func TestPrintUpdateSummary(t *testing.T) {
+	// GIVEN: expected files created and updated in update mode
+	dir := t.TempDir()
+	created := filepath.Join(dir, "created.expected.json")
+	updated := filepath.Join(dir, "updated.expected.json")
+
+	err := os.WriteFile(updated, []byte(`{"a": 1}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testastic.AssertJSON(t, created, `{"a": 1}`, testastic.Update())
+	testastic.AssertJSON(t, updated, `{"a": 2}`, testastic.Update())
+
+	// WHEN: printing the update summary
+	var buf bytes.Buffer
+	testastic.PrintUpdateSummary(&buf)
+
+	// THEN: both changes are listed
+	testastic.Contains(t, buf.String(), "expected files changed")
+	testastic.Contains(t, buf.String(), "created "+created)
+	testastic.Contains(t, buf.String(), "updated "+updated)
+}
+
+func TestAssertJSONString_ContextLines(t *testing.T) {
+	// GIVEN: a large document with a single changed field
+	exp := make(map[string]any)
+	act := make(map[string]any)
+
+	for i := range 20 {
+		key := fmt.Sprintf("k%02d", i)
+		exp[key], act[key] = i, i
+	}
+
+	act["k10"] = 99
+
+	expected, _ := json.Marshal(exp)
+	actual, _ := json.Marshal(act)
+
+	// WHEN: asserting with one line of context
+	r := &recordingReporter{}
+	testastic.AssertJSONString(r, string(expected), string(actual), testastic.ContextLines(1))
+
+	// THEN: unchanged lines away from the change are collapsed into hunk separators
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], `"k09": 9`)
+	testastic.Contains(t, r.errors[0], `"k11": 11`)
+	testastic.NotContains(t, r.errors[0], `"k08": 8`)
+	testastic.Contains(t, r.errors[0], "@@ 10 unchanged lines @@")
+	testastic.Contains(t, r.errors[0], "@@ 9 unchanged lines @@")
+}
+
+func TestCompareJSON_TreatNullAsAbsent(t *testing.T) {
+	// GIVEN: expected and actual that differ in omitting null fields
+	expected := []byte(`{"name": "Alice", "nickname": null, "age": 30}`)
+	actual := []byte(`{"name": "Alice", "age": 31, "avatar": null}`)
+
+	// WHEN: comparing with TreatNullAsAbsent
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.TreatNullAsAbsent())
+
+	// THEN: only the non-null difference is reported
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "$.age", diffs[0].Path)
+
+	// WHEN: comparing without the option
+	diffs, err = testastic.CompareJSON(expected, actual)
+
+	// THEN: the null fields are reported as removed and added
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 3)
+}
+
+func TestSetDefaultOptions(t *testing.T) {
+	// GIVEN: default options ignoring array order and a field
+	testastic.SetDefaultOptions(testastic.IgnoreArrayOrder(), testastic.IgnoreFields("id"), testastic.MaxDiffs(5))
+	t.Cleanup(func() { testastic.SetDefaultOptions() })
+
+	// WHEN: asserting without call-site options
+	// THEN: the defaults apply
+	testastic.AssertJSONString(t, `{"id": 1, "tags": ["a", "b"]}`, `{"id": 2, "tags": ["b", "a"]}`)
+
+	// WHEN: call-site options set the same value or add to the defaults
+	r := &recordingReporter{}
+	testastic.AssertJSONString(r, `{"a": 1, "b": 1, "id": 1}`, `{"a": 2, "b": 2, "id": 2}`, testastic.FirstDiffOnly())
+	testastic.AssertJSONString(t, `{"id": 1, "name": "x"}`, `{"id": 2, "name": "y"}`, testastic.IgnoreFields("name"))
+
+	// THEN: the call-site value overrides the default, and lists are combined
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], "... and 1 more differences")
+
+	// WHEN: the defaults are removed
+	testastic.SetDefaultOptions()
+	r = &recordingReporter{}
+	testastic.AssertJSONString(r, `{"id": 1}`, `{"id": 2}`)
+
+	// THEN: the comparison is strict again
+	testastic.Len(t, r.errors, 1)
+}
+
+func TestAssertJSONNotEqual(t *testing.T) {
+	// GIVEN: an expected file with a matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "before.expected.json")
+	writeTestFile(t, expectedFile, `{"id": "{{anyString}}", "status": "pending"}`)
+
+	// WHEN: the actual document differs
+	// THEN: the assertion passes
+	testastic.AssertJSONNotEqual(t, expectedFile, `{"id": "a1", "status": "active"}`)
+
+	// WHEN: the actual document only differs where a matcher or option allows it
+	r := &recordingReporter{}
+	testastic.AssertJSONNotEqual(r, expectedFile, `{"id": "b2", "status": "pending"}`)
+	testastic.AssertJSONNotEqual(r, expectedFile, `{"id": "c3", "status": "active"}`, testastic.IgnoreFields("status"))
+
+	// THEN: each assertion fails
+	testastic.Len(t, r.errors, 2)
+	testastic.Contains(t, r.errors[0], "AssertJSONNotEqual")
+	testastic.Contains(t, r.errors[0], "no differences")
+}
+
+func TestNewJSON(t *testing.T) {
+	// GIVEN: an asserter with shared options
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "user.expected.json")
+	writeTestFile(t, expectedFile, `{"id": 1, "tags": ["a", "b"], "name": "Alice"}`)
+
+	api := testastic.NewJSON(testastic.IgnoreFields("id"), testastic.IgnoreArrayOrder())
+
+	// WHEN: asserting documents that differ only in ways the options ignore
+	// THEN: they pass
+	api.Assert(t, expectedFile, `{"id": 2, "tags": ["b", "a"], "name": "Alice"}`)
+	api.Assert(t, expectedFile, []byte(`{"id": 3, "tags": ["a", "b"], "name": "Alice"}`))
+	api.AssertString(t, `{"id": 1, "tags": ["x", "y"]}`, `{"id": 9, "tags": ["y", "x"]}`)
+
+	// WHEN: asserting with an additional per-call option, or a real difference
+	r := &recordingReporter{}
+	api.Assert(r, expectedFile, `{"id": 2, "tags": ["b", "a"], "name": "Bob"}`)
+	api.Assert(r, expectedFile, `{"id": 2, "tags": ["b", "a"], "name": "Bob"}`, testastic.IgnoreFields("name"))
+
+	// THEN: the shared and per-call options are both applied
+	testastic.Len(t, r.errors, 1)
+	testastic.Contains(t, r.errors[0], "Bob")
+}
+
+func TestAssertJSONPath(t *testing.T) {
+	// GIVEN: an actual document with nested values
+	actual := `{"user": {"id": 7, "roles": ["admin", "editor"]}}`
+
+	// WHEN: asserting values at paths
+	// THEN: matching values pass, including matchers
+	testastic.AssertJSONPath(t, actual, "$.user.roles[0]", "admin")
+	testastic.AssertJSONPath(t, actual, "$.user.id", testastic.AnyInt())
+	testastic.AssertJSONPath(t, actual, "$.user.roles", []string{"admin", "editor"})
+
+	// WHEN: the value differs, or a segment of the path is missing
+	r := &recordingReporter{}
+	testastic.AssertJSONPath(r, actual, "$.user.roles[1]", "viewer")
+	testastic.AssertJSONPath(r, actual, "$.user.roles[5].name", "x")
+	testastic.AssertJSONPath(r, actual, "$.account.id", 1)
+
+	// THEN: each failure is reported, naming the first missing segment
+	testastic.Len(t, r.errors, 3)
+	testastic.Contains(t, r.errors[0], "viewer")
+	testastic.Contains(t, r.errors[1], "$.user.roles[5] not found")
+	testastic.Contains(t, r.errors[2], "$.account not found")
+}
+
+func TestAssertJSON_Record(t *testing.T) {
+	// GIVEN: an existing expected file with a matcher that the actual value satisfies
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.expected.json")
+	missing := filepath.Join(dir, "nested", "missing.expected.json")
+
+	err := os.WriteFile(existing, []byte(`{"id": "{{anyString}}"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: asserting in record mode
+	r := &recordingReporter{}
+	testastic.AssertJSON(r, existing, `{"id": "abc"}`, testastic.Record())
+	testastic.AssertJSON(r, missing, `{"id": "def"}`, testastic.Record())
+
+	// THEN: both files are written with the actual values, without failures
+	if len(r.errors) != 0 || len(r.fatals) != 0 {
+		t.Fatalf("expected no failures, got errors %v, fatals %v", r.errors, r.fatals)
+	}
+
+	for file, want := range map[string]string{existing: "abc", missing: "def"} {
+		content, readErr := os.ReadFile(file)
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+
+		testastic.Contains(t, string(content), `"id": "`+want+`"`)
+	}
+
+	testastic.Len(t, r.logs, 2)
+	testastic.Contains(t, r.logs[0], "recorded expected file")
+}
+
+func TestCompareJSON_SortArrayByKey(t *testing.T) {
+	// GIVEN: arrays of objects in different orders, with one changed item and one item without the key
+	expected := []byte(`{"users": [{"id": 2, "name": "Bob"}, {"name": "anon"}, {"id": 1, "name": "Alice"}]}`)
+	actual := []byte(`{"users": [{"name": "anon"}, {"id": 1, "name": "Alice"}, {"id": 2, "name": "Robert"}]}`)
+
+	// WHEN: comparing with SortArrayByKey
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.SortArrayByKey("$.users", "id"))
+
+	// THEN: only the changed item is reported at its sorted position
+	testastic.NoError(t, err)
+
+	if len(diffs) != 1 || diffs[0].Path != "$.users[1].name" {
+		t.Errorf("expected a single difference at $.users[1].name, got %v", diffs)
+	}
+}
+
+func TestCompareJSON_UnorderedReportsPairing(t *testing.T) {
+	// GIVEN: a reordered array where one element also changed
+	expected := []byte(`{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}]}`)
+	actual := []byte(`{"items": [{"id": 3, "name": "c"}, {"id": 1, "name": "a"}, {"id": 2, "name": "B"}]}`)
+
+	// WHEN: comparing with IgnoreArrayOrder
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.IgnoreArrayOrder())
+
+	// THEN: the field difference is reported with the matched actual index
+	testastic.NoError(t, err)
+
+	if len(diffs) != 1 || diffs[0].Path != "$.items[1].name" {
+		t.Fatalf("expected a single difference at $.items[1].name, got %v", diffs)
+	}
+
+	testastic.Contains(t, diffs[0].Reason, "best matches actual index 2")
+}
+
+func TestCompareJSON_UnorderedDifferentLengths(t *testing.T) {
+	// GIVEN: a reordered array where one element was dropped and another changed
+	expected := []byte(`{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}]}`)
+	actual := []byte(`{"items": [{"id": 3, "name": "C"}, {"id": 1, "name": "a"}]}`)
+
+	// WHEN: comparing with IgnoreArrayOrder
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.IgnoreArrayOrder())
+
+	// THEN: the changed element is paired and the dropped one is reported as removed
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 2)
+	testastic.Equal(t, "$.items[1]", diffs[0].Path)
+	testastic.Equal(t, testastic.DiffRemoved, diffs[0].Type)
+	testastic.Equal(t, "$.items[2].name", diffs[1].Path)
+	testastic.Contains(t, diffs[1].Reason, "best matches actual index 0")
+
+	// WHEN: an element was added instead
+	diffs, err = testastic.CompareJSON(expected,
+		[]byte(`{"items": [{"id": 4, "name": "d"}, {"id": 3, "name": "c"}, {"id": 2, "name": "b"}, {"id": 1, "name": "a"}]}`),
+		testastic.IgnoreArrayOrder())
+
+	// THEN: only the unpaired actual element is reported as added
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "$.items[0]", diffs[0].Path)
+	testastic.Equal(t, testastic.DiffAdded, diffs[0].Type)
+	testastic.Contains(t, diffs[0].Reason, "actual index 0 is not paired")
+}
+
+func TestCheckJSON(t *testing.T) {
+	// GIVEN: an expected file
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "user.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"name": "Alice", "age": 30}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: checking matching and mismatching values
+	passing := testastic.CheckJSON(t, expectedFile, testJSONAliceAge30)
+
+	r := &recordingReporter{}
+	failing := testastic.CheckJSON(r, expectedFile, `{"name": "Bob", "age": 30}`)
+
+	// THEN: the differences are returned and failures are still reported
+	testastic.Empty(t, passing)
+
+	if len(failing) != 1 || failing[0].Path != "$.name" {
+		t.Errorf("expected a single difference at $.name, got %v", failing)
+	}
+
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "CheckJSON") {
+		t.Errorf("expected CheckJSON failure to be reported, got %v", r.errors)
+	}
+}
+
+func TestAssertJSONStream(t *testing.T) {
+	// GIVEN: an expected stream of documents with matchers
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "events.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"type": "start", "id": {{anyInt}}}
+{"type": "stop", "id": {{anyInt}}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: asserting a concatenated stream without separators
+	// THEN: the documents are compared pairwise
+	testastic.AssertJSONStream(t, expectedFile, `{"type":"start","id":1}{"type":"stop","id":2}`)
+
+	// WHEN: the order differs and IgnoreStreamOrder is set
+	// THEN: the documents still match
+	testastic.AssertJSONStream(t, expectedFile, `{"type":"stop","id":2}{"type":"start","id":1}`,
+		testastic.IgnoreStreamOrder())
+}
+
+func TestAssertJSONStream_Mismatch(t *testing.T) {
+	// GIVEN: an expected stream of documents
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "events.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"type": "start"}{"type": "stop"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: the second document differs
+	testastic.AssertJSONStream(r, expectedFile, `{"type": "start"}{"type": "pause"}`)
+
+	// THEN: the failure is reported
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "AssertJSONStream") {
+		t.Fatalf("expected AssertJSONStream failure, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+
+	// WHEN: comparing regardless of order
+	r = &recordingReporter{}
+	testastic.AssertJSONStream(r, expectedFile, `{"type": "pause"}{"type": "start"}`, testastic.IgnoreStreamOrder())
+
+	// THEN: the unmatched document is named by its index
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "expected element $[1] best matches actual index 0") {
+		t.Errorf("expected failure naming the document index, got errors=%v", r.errors)
+	}
+}
+
+func TestAssertJSONStream_UpdateKeepsMatchersInTheirDocument(t *testing.T) {
+	// GIVEN: an expected stream where only the first document has a matcher for "id"
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "events.expected.json")
+	writeTestFile(t, expectedFile, `{"id": "{{anyString}}", "n": 1}{"id": 5, "n": 2}`)
+
+	// WHEN: updating with a changed second document
+	testastic.AssertJSONStream(t, expectedFile, `{"id": "a", "n": 1}{"id": 5, "n": 3}`, testastic.Update())
+
+	// THEN: the matcher stays in the first document and the literal in the second
+	content, err := os.ReadFile(expectedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\n  \"id\": \"{{anyString}}\",\n  \"n\": 1\n}\n{\n  \"id\": 5,\n  \"n\": 3\n}\n"
+	testastic.Equal(t, want, string(content))
+}
+
+func TestFormatDiffJSON(t *testing.T) {
+	// GIVEN: differences including a matcher failure
+	diffs := []testastic.Difference{
+		{Path: "$.id", Expected: testastic.AnyInt(), Actual: "abc", Type: testastic.DiffMatcherFailed},
+		{Path: "$.extra", Expected: nil, Actual: float64(1), Type: testastic.DiffAdded},
+	}
+
+	// WHEN: formatting as JSON
+	out, err := testastic.FormatDiffJSON(diffs)
+
+	// THEN: the output is a JSON array with matchers rendered as expressions
+	testastic.NoError(t, err)
+	testastic.AssertJSONEqual(t, []byte(`[
+		{"path": "$.id", "type": "matcher failed", "expected": "{{anyInt}}", "actual": "abc"},
+		{"path": "$.extra", "type": "added", "expected": null, "actual": 1}
+	]`), []byte(out))
+}
+
+func TestAssertJSONString_DiffFormatJSON(t *testing.T) {
+	// GIVEN: a mismatching value
+	r := &recordingReporter{}
+
+	// WHEN: asserting with DiffFormatJSON
+	testastic.AssertJSONString(r, `{"name": "Alice"}`, `{"name": "Bob"}`, testastic.DiffFormatJSON())
+
+	// THEN: the failure contains the JSON diff
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `"path": "$.name"`) {
+		t.Errorf("expected JSON diff output, got %v", r.errors)
+	}
+}
+
+func TestAssertYAML(t *testing.T) {
+	// GIVEN: an expected YAML file with matchers and an unordered list
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "config.expected.yaml")
+
+	err := os.WriteFile(expectedFile, []byte(`name: Alice
+id: {{anyString}}
+replicas: 3
+ratio: 0.5
+enabled: true
+owner: null
+roles:
+  "{{unordered}}": [admin, editor]
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: asserting YAML with floats written as integers and reordered roles
+	// THEN: the assertion passes
+	testastic.AssertYAML(t, expectedFile, `
+name: Alice
+id: abc-123
+replicas: 3.0
+ratio: 0.5
+enabled: true
+owner: ~
+roles: [editor, admin]
+`)
+}
+
+func TestAssertYAML_Mismatch(t *testing.T) {
+	// GIVEN: an expected YAML file
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "config.expected.yaml")
+
+	err := os.WriteFile(expectedFile, []byte("name: Alice\nport: 8080\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: a value differs
+	testastic.AssertYAML(r, expectedFile, "name: Alice\nport: 9090\n")
+
+	// THEN: the failure shows the differing value
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "AssertYAML") || !strings.Contains(r.errors[0], `"port": 9090`) {
+		t.Errorf("expected AssertYAML failure for port, got errors=%v fatals=%v", r.errors, r.fatals)
+	}
+}
+
+func TestAssertYAML_UpdatePreservesMatchers(t *testing.T) {
+	// GIVEN: an expected YAML file with a matcher
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "config.expected.yaml")
+
+	err := os.WriteFile(expectedFile, []byte("id: {{anyString}}\nport: 8080\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: updating with a changed value
+	testastic.AssertYAML(t, expectedFile, "id: xyz\nport: 9090\n", testastic.Update())
+
+	// THEN: the matcher is kept and the value is updated
+	content, err := os.ReadFile(expectedFile)
+	testastic.NoError(t, err)
+	testastic.Equal(t, "id: {{anyString}}\nport: 9090\n", string(content))
+}
+
+func TestCompareJSON_LeafValuesOnly(t *testing.T) {
+	// GIVEN: documents that differ in structure and in one shared leaf
+	expected := []byte(`{"name": "Alice", "age": 30, "address": {"city": "Berlin"},
+		"tags": ["a", "b", "c"], "meta": {"v": 1}, "extra": "gone"}`)
+	actual := []byte(`{"name": "Alice", "age": 31, "profile": {"bio": "hi"},
+		"tags": ["a", "b"], "meta": [1]}`)
+
+	// WHEN: comparing only leaf values
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.LeafValuesOnly())
+
+	// THEN: only the co-occurring scalar that differs is reported
+	testastic.NoError(t, err)
+
+	if len(diffs) != 1 || diffs[0].Path != "$.age" {
+		t.Errorf("expected only $.age to differ, got %v", diffs)
+	}
+}
+
+func TestCompareJSON_LeafValuesOnlyArrays(t *testing.T) {
+	// GIVEN: arrays of differing length
+	expected := []byte(`{"ordered": [1, 2, 3], "unordered": [3, 9]}`)
+	actual := []byte(`{"ordered": [1, 5], "unordered": [1, 2, 3]}`)
+
+	// WHEN: comparing only leaf values with one unordered path
+	diffs, err := testastic.CompareJSON(expected, actual,
+		testastic.LeafValuesOnly(), testastic.IgnoreArrayOrderAt("$.unordered"))
+
+	// THEN: ordered arrays compare the common prefix and unordered elements pair up
+	testastic.NoError(t, err)
+
+	if len(diffs) != 2 || diffs[0].Path != "$.ordered[1]" || diffs[1].Path != "$.unordered[1]" {
+		t.Errorf("expected $.ordered[1] and $.unordered[1] to differ, got %v", diffs)
+	}
+}
+
+func TestAssertJSONString_WithOutput(t *testing.T) {
+	// GIVEN: a writer to capture the failure text
+	var buf bytes.Buffer
+
+	r := &recordingReporter{}
+
+	// WHEN: asserting a mismatch with WithOutput
+	testastic.AssertJSONString(r, `{"name": "Alice"}`, `{"name": "Bob"}`, testastic.WithOutput(&buf))
+
+	// THEN: the test still fails and the writer receives the same failure text
+	if len(r.errors) != 1 {
+		t.Fatalf("expected one error, got %v", r.errors)
+	}
+
+	testastic.Equal(t, r.errors[0], buf.String())
+}
+
+func TestCompareJSON_MatcherFailureReasons(t *testing.T) {
+	// GIVEN: values rejected by different built-in matchers
+	expected := []byte(`{"code": "{{regex ` + "`^[A-Z]{3}$`" + `}}", "status": "{{oneOf \"a\" \"b\"}}",
+		"count": "{{anyInt}}", "port": "{{numberBetween 1 100}}", "name": "{{anyString}}"}`)
+	actual := []byte(`{"code": "ab", "status": "c", "count": 1.5, "port": 150, "name": 7}`)
+
+	// WHEN: comparing
+	diffs, err := testastic.CompareJSON(expected, actual)
+
+	// THEN: each difference explains why the matcher rejected the value
+	testastic.NoError(t, err)
+
+	want := map[string]string{
+		"$.code":   "value does not match pattern `^[A-Z]{3}$`",
+		"$.count":  "expected integer, got decimal 1.5",
+		"$.name":   "expected string, got number",
+		"$.port":   "150 is outside range [1, 100]",
+		"$.status": `"c" is not in allowed set {"a", "b"}`,
+	}
+
+	testastic.Len(t, diffs, len(want))
+
+	for _, d := range diffs {
+		testastic.Equal(t, want[d.Path], d.Reason)
+	}
+
+	// THEN: FormatDiff renders the explanation
+	testastic.Contains(t, testastic.FormatDiff(diffs), "reason:   expected integer, got decimal 1.5")
+}
+
+func TestCompareJSON_FieldAlias(t *testing.T) {
+	// GIVEN: an expected file using the old field name
+	expected := []byte(`{"user": {"emailAddress": "a@example.com", "name": "Alice"}}`)
+	renamed := []byte(`{"user": {"email": "a@example.com", "name": "Alice"}}`)
+	original := []byte(`{"user": {"emailAddress": "a@example.com", "name": "Alice"}}`)
+	alias := testastic.FieldAlias("$.user.emailAddress", "email")
+
+	// WHEN: comparing against the renamed and the original API
+	// THEN: both match
+	for _, actual := range [][]byte{renamed, original} {
+		diffs, err := testastic.CompareJSON(expected, actual, alias)
+		testastic.NoError(t, err)
+		testastic.Empty(t, diffs)
+	}
+
+	// WHEN: the aliased value differs
+	diffs, err := testastic.CompareJSON(expected, []byte(`{"user": {"email": "b@example.com", "name": "Alice"}}`), alias)
+
+	// THEN: the difference is reported at the expected path with the alias noted
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "$.user.emailAddress", diffs[0].Path)
+	testastic.Contains(t, diffs[0].Reason, `actual key "email"`)
+
+	// WHEN: the original key differs and the alias key is absent
+	diffs, err = testastic.CompareJSON(expected, []byte(`{"user": {"emailAddress": "b@example.com", "name": "Alice"}}`), alias)
+
+	// THEN: the difference has no alias note
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "", diffs[0].Reason)
+
+	// WHEN: actual has both keys and the original one holds a stale value
+	both := []byte(`{"user": {"emailAddress": "STALE", "email": "a@example.com", "name": "Alice"}}`)
+	diffs, err = testastic.CompareJSON(expected, both, alias)
+
+	// THEN: the unread original key is reported as added
+	testastic.NoError(t, err)
+	testastic.Len(t, diffs, 1)
+	testastic.Equal(t, "$.user.emailAddress", diffs[0].Path)
+	testastic.Equal(t, testastic.DiffAdded, diffs[0].Type)
+	testastic.Contains(t, diffs[0].Reason, `actual key "email"`)
+
+	// WHEN: extra fields are allowed
+	diffs, err = testastic.CompareJSON(expected, both, alias, testastic.AllowExtraFields())
+
+	// THEN: the original key is ignored
+	testastic.NoError(t, err)
+	testastic.Empty(t, diffs)
+}
+
+func TestFormatDiff_FloatDisplayPrecision(t *testing.T) {
+	// GIVEN: a float computed as 0.1+0.2 compared with a clearly different value
+	a, b := 0.1, 0.2
+	sum := a + b
+	diffs := []testastic.Difference{{Path: "$.total", Expected: 0.5, Actual: sum, Type: testastic.DiffChanged}}
+
+	// WHEN: formatting
+	out := testastic.FormatDiff(diffs)
+
+	// THEN: the float is rounded for display
+	testastic.Contains(t, out, "actual:   0.3\n")
+
+	// WHEN: the values only differ beyond the display precision
+	diffs[0].Expected = 0.3
+	out = testastic.FormatDiff(diffs)
+
+	// THEN: both are shown with full precision
+	testastic.Contains(t, out, "expected: 0.3\n")
+	testastic.Contains(t, out, "actual:   0.30000000000000004\n")
+}
+
+func TestAssertJSONCapture(t *testing.T) {
+	// GIVEN: an expected file with matchers for generated values
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "create.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"id": "{{anyString}}", "items": [{"token": "{{anyString}}"}]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: asserting and capturing the generated values
+	vals := testastic.AssertJSONCapture(t, expectedFile, `{"id": "u-42", "items": [{"token": "abc"}]}`,
+		[]string{"$.id", "$.items[0].token"})
+
+	// THEN: the actual values are returned by path
+	testastic.Equal(t, "u-42", vals["$.id"])
+	testastic.Equal(t, "abc", vals["$.items[0].token"])
+}
+
+func TestAssertJSONCapture_OptionsAndLargeIntegers(t *testing.T) {
+	// GIVEN: an expected file and an actual document with a large integer id
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "create.expected.json")
+	writeTestFile(t, expectedFile, `{"id": "{{anyValue}}", "created": "2024-01-01"}`)
+
+	actual := `{"id": 12345678901234567890, "created": "2025-06-30"}`
+
+	// WHEN: capturing with options
+	vals := testastic.AssertJSONCapture(t, expectedFile, actual, []string{"$.id"},
+		testastic.IgnoreFields("created"), testastic.UseJSONNumber())
+
+	// THEN: the options apply and the id keeps its precision
+	testastic.Equal(t, any(json.Number("12345678901234567890")), vals["$.id"])
+}
+
+func TestAssertJSONCapture_FailedAssertion(t *testing.T) {
+	// GIVEN: an expected file the actual document does not match
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "create.expected.json")
+	writeTestFile(t, expectedFile, `{"id": "{{anyString}}", "status": "created"}`)
+
+	r := &recordingReporter{}
+
+	// WHEN: capturing from a failing assertion
+	vals := testastic.AssertJSONCapture(r, expectedFile, `{"id": "u-42", "status": "error"}`, []string{"$.id"})
+
+	// THEN: the failure is reported and nothing is captured
+	testastic.Len(t, r.errors, 1)
+	testastic.Nil(t, vals)
+}
+
+func TestAssertJSONCapture_MissingPath(t *testing.T) {
+	// GIVEN: an expected file
+	dir := t.TempDir()
+	expectedFile := filepath.Join(dir, "create.expected.json")
+
+	err := os.WriteFile(expectedFile, []byte(`{"id": "{{anyString}}"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingReporter{}
+
+	// WHEN: capturing a path that does not exist
+	vals := testastic.AssertJSONCapture(r, expectedFile, `{"id": "u-42"}`, []string{"$.token"})
+
+	// THEN: the capture fails fatally and returns nil
+	testastic.Nil(t, vals)
+
+	if len(r.fatals) != 1 || !strings.Contains(r.fatals[0], "path $.token not found") {
+		t.Errorf("expected fatal for missing path, got %v", r.fatals)
+	}
+}
+
+func TestCompareJSON_SignificantFigures(t *testing.T) {
+	// GIVEN: measurements of very different magnitudes that agree at 4 significant figures
+	expected := []byte(`{"mass": 1.23441e-9, "distance": 149597870700, "count": 10}`)
+	actual := []byte(`{"mass": 1.23439e-9, "distance": 149600000000, "count": 11}`)
+
+	// WHEN: comparing at 4 significant figures
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.SignificantFigures(4))
+
+	// THEN: only the value differing at 4 significant figures is reported
+	testastic.NoError(t, err)
+
+	if len(diffs) != 1 || diffs[0].Path != "$.count" {
+		t.Errorf("expected only $.count to differ, got %v", diffs)
+	}
+
+	// WHEN: an absolute tolerance applies at a path
+	diffs, err = testastic.CompareJSON(expected, actual,
+		testastic.SignificantFigures(4), testastic.EpsilonAt("$.distance", 1))
+
+	// THEN: EpsilonAt takes precedence over SignificantFigures there
+	testastic.NoError(t, err)
+
+	if len(diffs) != 2 || diffs[0].Path != "$.count" || diffs[1].Path != "$.distance" {
+		t.Errorf("expected $.count and $.distance to differ, got %v", diffs)
+	}
+}
+
+func TestCompareJSON_CoverageReport(t *testing.T) {
+	// GIVEN: an expected document with concrete values, matchers and ignored fields
+	expected := []byte(`{"id": "{{anyString}}", "name": "Alice", "meta": {"etag": "{{ignore}}", "tags": []},
+		"items": [{"sku": "a", "updatedAt": "x"}], "createdAt": "y"}`)
+	actual := []byte(`{"id": "u-1", "name": "Alice", "meta": {"etag": "abc", "tags": []},
+		"items": [{"sku": "a", "updatedAt": "z"}], "createdAt": "w"}`)
+
+	var cov testastic.Coverage
+
+	// WHEN: comparing with a coverage report
+	diffs, err := testastic.CompareJSON(expected, actual, testastic.CoverageReport(&cov),
+		testastic.IgnoreFields("createdAt"), testastic.IgnoreFieldsMatching("$.items[*].updatedAt"))
+
+	// THEN: compared leaves and ignored paths are listed separately
+	testastic.NoError(t, err)
+	testastic.Empty(t, diffs)
+	testastic.SliceEqual(t, []string{"$.id", "$.items[0].sku", "$.meta.tags", "$.name"}, cov.Compared)
+	testastic.SliceEqual(t, []string{"$.createdAt", "$.items[0].updatedAt", "$.meta.etag"}, cov.Ignored)
+}
+
+func TestAssertJSONString_MaxDiffs(t *testing.T) {
+	// GIVEN: documents with several differences
+	expected := `{"a": 1, "b": 2, "c": 3, "d": 4}`
+	actual := `{"a": 9, "b": 9, "c": 9, "d": 9}`
+	r := &recordingReporter{}
+
+	// WHEN: asserting with FirstDiffOnly
+	testastic.AssertJSONString(r, expected, actual, testastic.FirstDiffOnly())
+
+	// THEN: only the first difference is shown, with a count of the rest
+	if len(r.errors) != 1 {
+		t.Fatalf("expected one failure, got %v", r.errors)
+	}
+
+	testastic.Contains(t, r.errors[0], "$.a")
+	testastic.NotContains(t, r.errors[0], "$.b")
+	testastic.Contains(t, r.errors[0], "... and 3 more differences")
+}
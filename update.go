@@ -3,10 +3,12 @@ package testastic
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // File permission constants for test data files.
@@ -15,13 +17,66 @@ const (
 	filePerm = 0o644
 )
 
+// updateEvent records an expected file that was created or updated.
+type updateEvent struct {
+	action string // "created", "updated" or "recorded"
+	path   string
+}
+
+//nolint:gochecknoglobals // Update events are collected across tests for PrintUpdateSummary.
+var (
+	updateEventsMu sync.Mutex
+	updateEvents   []updateEvent
+)
+
+// recordUpdate records that an expected file was created, updated or recorded.
+func recordUpdate(action, path string) {
+	updateEventsMu.Lock()
+	defer updateEventsMu.Unlock()
+
+	updateEvents = append(updateEvents, updateEvent{action: action, path: path})
+}
+
+// PrintUpdateSummary writes a summary of all expected files created or updated in
+// update mode, or written in record mode, so far. Call it from TestMain after
+// m.Run. Nothing is written if no files changed.
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		testastic.PrintUpdateSummary(os.Stdout)
+//		os.Exit(code)
+//	}
+func PrintUpdateSummary(w io.Writer) {
+	updateEventsMu.Lock()
+	events := slices.Clone(updateEvents)
+	updateEventsMu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+
+	if len(events) == 1 {
+		sb.WriteString("testastic: 1 expected file changed\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("testastic: %d expected files changed\n", len(events)))
+	}
+
+	for _, e := range events {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", e.action, e.path))
+	}
+
+	_, _ = io.WriteString(w, sb.String())
+}
+
 // updateExpectedFile updates the expected file with the actual value.
 // It preserves template matchers from the original file.
 func updateExpectedFile(path string, actual []byte, expected *ExpectedJSON) error {
-	// Parse actual JSON
-	var actualData any
-
-	unmarshalErr := json.Unmarshal(actual, &actualData)
+	// Parse actual JSON, keeping numbers as written so unchanged values stay byte-identical
+	actualData, unmarshalErr := decodeJSON(actual, true)
 	if unmarshalErr != nil {
 		return fmt.Errorf("failed to parse actual JSON for update: %w", unmarshalErr)
 	}
@@ -29,8 +84,14 @@ func updateExpectedFile(path string, actual []byte, expected *ExpectedJSON) erro
 	// Get matcher positions from original expected file
 	matcherPositions := expected.ExtractMatcherPositions()
 
+	// Keep arrays marked as unordered wrapped
+	actualData = wrapUnordered(actualData, "$", expected.UnorderedPaths)
+
+	// Keep the key order of the original file, so unchanged keys do not move
+	keyOrder := expectedKeyOrder(expected.Raw, false)
+
 	// Generate updated JSON with matchers preserved
-	updatedJSON, err := generateUpdatedJSON(actualData, matcherPositions)
+	updatedJSON, err := generateUpdatedJSON(actualData, "$", matcherPositions, keyOrder)
 	if err != nil {
 		return fmt.Errorf("failed to generate updated JSON: %w", err)
 	}
@@ -52,12 +113,42 @@ func updateExpectedFile(path string, actual []byte, expected *ExpectedJSON) erro
 	return nil
 }
 
+// wrapUnordered re-wraps arrays at the given paths in {"{{unordered}}": [...]}.
+func wrapUnordered(data any, path string, paths []string) any {
+	if len(paths) == 0 {
+		return data
+	}
+
+	switch v := data.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, val := range v {
+			result[key] = wrapUnordered(val, path+"."+key, paths)
+		}
+
+		return result
+
+	case []any:
+		result := make([]any, len(v))
+		for i, val := range v {
+			result[i] = wrapUnordered(val, fmt.Sprintf("%s[%d]", path, i), paths)
+		}
+
+		if slices.Contains(paths, path) {
+			return map[string]any{"{{" + unorderedKeyword + "}}": result}
+		}
+
+		return result
+
+	default:
+		return v
+	}
+}
+
 // createExpectedFile creates a new expected file from actual data.
 func createExpectedFile(path string, actual []byte) error {
-	// Pretty-print the JSON
-	var data any
-
-	unmarshalErr := json.Unmarshal(actual, &data)
+	// Pretty-print the JSON, keeping numbers as written
+	data, unmarshalErr := decodeJSON(actual, true)
 	if unmarshalErr != nil {
 		return fmt.Errorf("failed to parse actual JSON: %w", unmarshalErr)
 	}
@@ -85,72 +176,203 @@ func createExpectedFile(path string, actual []byte) error {
 }
 
 // generateUpdatedJSON creates JSON output with matchers preserved at their original positions.
-func generateUpdatedJSON(data any, matcherPositions map[string]string) (string, error) {
-	// First, generate the pretty JSON
-	prettyJSON, err := json.MarshalIndent(data, "", "  ")
+// Object keys are written in the order given by keyOrder for the object's path, see
+// expectedKeyOrder; keys not listed there follow in alphabetical order.
+func generateUpdatedJSON(data any, path string, matcherPositions map[string]string, keyOrder map[string][]string) (string, error) {
+	w := &jsonWriter{matcherPositions: matcherPositions, keyOrder: keyOrder}
+
+	err := w.write(data, path, "")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	if len(matcherPositions) == 0 {
-		return string(prettyJSON) + "\n", nil
+	return w.sb.String() + "\n", nil
+}
+
+// jsonWriter writes JSON indented like json.MarshalIndent with a two-space indent, but
+// with object keys ordered by keyOrder instead of alphabetically, and with the template
+// expression from matcherPositions in place of the value at each matcher's path.
+type jsonWriter struct {
+	sb               strings.Builder
+	matcherPositions map[string]string
+	keyOrder         map[string][]string
+}
+
+// write writes data found at path, with nested lines indented by indent.
+func (w *jsonWriter) write(data any, path, indent string) error {
+	if expr, ok := w.matcherPositions[path]; ok {
+		w.sb.WriteString(`"` + expr + `"`)
+
+		return nil
 	}
 
-	// Replace values at matcher positions with the original matcher expressions
-	result := string(prettyJSON)
-	for path, matcherExpr := range matcherPositions {
-		result = replaceValueAtPath(result, path, matcherExpr)
+	switch v := data.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			w.sb.WriteString("{}")
+
+			return nil
+		}
+
+		w.sb.WriteString("{\n")
+
+		for i, key := range orderedKeys(v, w.keyOrder[path]) {
+			if i > 0 {
+				w.sb.WriteString(",\n")
+			}
+
+			keyJSON, err := json.Marshal(key)
+			if err != nil {
+				return err //nolint:wrapcheck // Wrapped by generateUpdatedJSON.
+			}
+
+			w.sb.WriteString(indent + "  ")
+			w.sb.Write(keyJSON)
+			w.sb.WriteString(": ")
+
+			// The array inside an unordered wrapper shares the wrapper's path.
+			childPath := path + "." + key
+			if key == "{{"+unorderedKeyword+"}}" {
+				childPath = path
+			}
+
+			err = w.write(v[key], childPath, indent+"  ")
+			if err != nil {
+				return err
+			}
+		}
+
+		w.sb.WriteString("\n" + indent + "}")
+
+	case []any:
+		if len(v) == 0 {
+			w.sb.WriteString("[]")
+
+			return nil
+		}
+
+		w.sb.WriteString("[\n")
+
+		for i, val := range v {
+			if i > 0 {
+				w.sb.WriteString(",\n")
+			}
+
+			w.sb.WriteString(indent + "  ")
+
+			err := w.write(val, fmt.Sprintf("%s[%d]", path, i), indent+"  ")
+			if err != nil {
+				return err
+			}
+		}
+
+		w.sb.WriteString("\n" + indent + "]")
+
+	default:
+		valueJSON, err := json.Marshal(v)
+		if err != nil {
+			return err //nolint:wrapcheck // Wrapped by generateUpdatedJSON.
+		}
+
+		w.sb.Write(valueJSON)
 	}
 
-	return result + "\n", nil
+	return nil
 }
 
-// replaceValueAtPath replaces the value at a JSON path with a matcher expression.
-// This is a simplified implementation that works for common cases.
-func replaceValueAtPath(jsonStr, path, matcherExpr string) string {
-	// Convert path to key name
-	// e.g., "$.user.id" -> "id"
-	parts := strings.Split(path, ".")
-	if len(parts) == 0 {
-		return jsonStr
+// orderedKeys returns the keys of obj, first those listed in order, then the rest sorted.
+func orderedKeys(obj map[string]any, order []string) []string {
+	keys := make([]string, 0, len(obj))
+
+	for _, key := range order {
+		if _, ok := obj[key]; ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
 	}
 
-	key := parts[len(parts)-1]
+	var rest []string
 
-	// Handle array index in key
-	if idx := strings.Index(key, "["); idx > 0 {
-		key = key[:idx]
+	for key := range obj {
+		if !slices.Contains(keys, key) {
+			rest = append(rest, key)
+		}
 	}
 
-	// Create regex to match "key": <value>
-	// This is a simplified approach that may not work for all cases
-	pattern := fmt.Sprintf(`("%s"\s*:\s*)((?:"[^"]*")|(?:\d+(?:\.\d+)?)|(?:true|false|null))`, regexp.QuoteMeta(key))
-	re := regexp.MustCompile(pattern)
+	slices.Sort(rest)
+
+	return append(keys, rest...)
+}
 
-	// Replace with matcher expression
-	result := re.ReplaceAllStringFunc(jsonStr, func(match string) string {
-		// Find the colon position
-		colonIdx := strings.Index(match, ":")
-		if colonIdx < 0 {
-			return match
+// expectedKeyOrder returns the order of object keys in the raw content of an expected
+// file, keyed by the path of each object. For a stream, documents are rooted at $[i]
+// like in AssertJSONStream. Content that cannot be tokenized yields the order collected
+// up to that point.
+func expectedKeyOrder(raw string, stream bool) map[string][]string {
+	placeholders := &ExpectedJSON{Matchers: make(map[string]string)}
+	dec := json.NewDecoder(strings.NewReader(substituteTemplateExprs(raw, placeholders)))
+	order := make(map[string][]string)
+
+	for i := 0; dec.More(); i++ {
+		path := "$"
+		if stream {
+			path = fmt.Sprintf("$[%d]", i)
 		}
 
-		prefix := match[:colonIdx+1]
-		// Preserve whitespace after colon
-		rest := match[colonIdx+1:]
+		if collectKeyOrder(dec, path, placeholders.Matchers, order) != nil || !stream {
+			break
+		}
+	}
+
+	return order
+}
+
+// collectKeyOrder reads the next JSON value from dec and records the key order of every
+// object in it.
+func collectKeyOrder(dec *json.Decoder, path string, matchers map[string]string, order map[string][]string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Only used to stop collecting.
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err //nolint:wrapcheck // Only used to stop collecting.
+			}
 
-		var whitespace strings.Builder
+			key, _ := keyTok.(string)
 
-		for _, c := range rest {
-			if c != ' ' && c != '\t' {
-				break
+			// The array inside an unordered wrapper shares the wrapper's path.
+			childPath := path + "." + key
+			if matchers[key] == unorderedKeyword {
+				key = "{{" + unorderedKeyword + "}}"
+				childPath = path
 			}
 
-			whitespace.WriteRune(c)
+			order[path] = append(order[path], key)
+
+			err = collectKeyOrder(dec, childPath, matchers, order)
+			if err != nil {
+				return err
+			}
 		}
 
-		return prefix + whitespace.String() + `"` + matcherExpr + `"`
-	})
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			err := collectKeyOrder(dec, fmt.Sprintf("%s[%d]", path, i), matchers, order)
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		return nil
+	}
+
+	// Consume the closing delimiter.
+	_, err = dec.Token()
 
-	return result
+	return err //nolint:wrapcheck // Only used to stop collecting.
 }
@@ -0,0 +1,377 @@
+package testastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedYAMLNode is returned when YAML contains a node that has no JSON equivalent.
+var ErrUnsupportedYAMLNode = errors.New("unsupported YAML node")
+
+// quotedMatcherRegex matches matcher expressions that yaml.Marshal wrapped in single quotes.
+var quotedMatcherRegex = regexp.MustCompile(`'(\{\{(?:[^']|'')*\}\})'`)
+
+// AssertYAML compares actual YAML against an expected YAML file.
+// YAML is converted to the JSON data model before comparison, so the same template
+// matchers, options and diff output as AssertJSON apply. Integers and floats are both
+// numbers, and mapping keys are converted to strings.
+// T can be: []byte, string, io.Reader, or any other value (marshaled with yaml.v3).
+//
+// Example expected file:
+//
+//	name: Alice
+//	id: {{anyString}}
+//	roles:
+//	  "{{unordered}}": [admin, editor]
+//
+//nolint:funlen // Main assertion flow needs sequential validation steps.
+func AssertYAML[T any](tb Reporter, expectedFile string, actual T, opts ...Option) {
+	tb.Helper()
+
+	actualBytes, err := toYAMLBytes(actual)
+	if err != nil {
+		tb.Fatalf("testastic: failed to convert actual to bytes: %v", err)
+
+		return
+	}
+
+	cfg := newConfig(opts...)
+
+	actualData, err := decodeYAML(actualBytes, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: failed to parse actual YAML: %v", err)
+
+		return
+	}
+
+	if cfg.Record {
+		recordErr := writeYAMLFile(expectedFile, actualData, nil)
+		if recordErr != nil {
+			tb.Fatalf("testastic: failed to record expected file: %v", recordErr)
+		}
+
+		recordUpdate("recorded", expectedFile)
+		tb.Logf("testastic: recorded expected file %s", expectedFile)
+
+		return
+	}
+
+	_, statErr := os.Stat(expectedFile)
+	if os.IsNotExist(statErr) {
+		if cfg.Update {
+			createErr := writeYAMLFile(expectedFile, actualData, nil)
+			if createErr != nil {
+				tb.Fatalf("testastic: failed to create expected file: %v", createErr)
+			}
+
+			recordUpdate("created", expectedFile)
+			tb.Logf("testastic: created expected file %s", expectedFile)
+
+			return
+		}
+
+		tb.Fatalf(
+			"testastic: expected file does not exist: %s (run with -update to create)",
+			expectedFile,
+		)
+
+		return
+	}
+
+	expected, err := parseExpectedYAMLFile(expectedFile, cfg.useNumber())
+	if err != nil {
+		tb.Fatalf("testastic: %v", err)
+
+		return
+	}
+
+	cfg.IgnoreArrayOrderPaths = append(cfg.IgnoreArrayOrderPaths, expected.UnorderedPaths...)
+
+	diffs := compareDocument(expected.Data, actualData, "$", cfg)
+
+	if cfg.Update && len(diffs) > 0 {
+		updateErr := writeYAMLFile(expectedFile, actualData, expected)
+		if updateErr != nil {
+			tb.Fatalf("testastic: failed to update expected file: %v", updateErr)
+		}
+
+		recordUpdate("updated", expectedFile)
+		tb.Logf("testastic: updated expected file %s", expectedFile)
+
+		return
+	}
+
+	if len(diffs) > 0 {
+		reportJSONFailure(tb, cfg, "AssertYAML ("+expectedFile+")", expected.Data, actualData, diffs)
+	}
+}
+
+// toYAMLBytes converts various input types to []byte of YAML.
+func toYAMLBytes[T any](v T) ([]byte, error) {
+	switch val := any(v).(type) {
+	case []byte:
+		return val, nil
+
+	case string:
+		return []byte(val), nil
+
+	case io.Reader:
+		data, err := io.ReadAll(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from io.Reader: %w", err)
+		}
+
+		return data, nil
+
+	default:
+		data, err := yaml.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+
+		return data, nil
+	}
+}
+
+// parseExpectedYAMLFile reads an expected YAML file and replaces template expressions
+// with matchers.
+func parseExpectedYAMLFile(path string, useNumber bool) (*ExpectedJSON, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // Path is controlled by test code.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected file: %w", err)
+	}
+
+	expected := &ExpectedJSON{
+		Matchers: make(map[string]string),
+		Raw:      string(content),
+	}
+
+	data, err := decodeYAML([]byte(substituteTemplateExprs(string(content), expected)), useNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected file as YAML: %w", err)
+	}
+
+	expected.Data, err = replacePlaceholders(data, "$", expected)
+	if err != nil {
+		return nil, err
+	}
+
+	return expected, nil
+}
+
+// decodeYAML parses a YAML document into the JSON data model.
+func decodeYAML(data []byte, useNumber bool) (any, error) {
+	var node yaml.Node
+
+	err := yaml.Unmarshal(data, &node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	return yamlNodeToJSON(&node, useNumber)
+}
+
+// yamlNodeToJSON converts a YAML node into the values produced by encoding/json:
+// map[string]any, []any, string, bool, nil, and float64 or json.Number.
+//
+//nolint:cyclop,funlen // Each YAML node kind and scalar tag needs its own branch.
+func yamlNodeToJSON(node *yaml.Node, useNumber bool) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+
+		return yamlNodeToJSON(node.Content[0], useNumber)
+
+	case yaml.AliasNode:
+		return yamlNodeToJSON(node.Alias, useNumber)
+
+	case yaml.MappingNode:
+		result := make(map[string]any, len(node.Content)/2) //nolint:mnd // Key and value nodes.
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			// Merge keys (<<: *anchor) copy the referenced mapping's entries.
+			if key.Tag == "!!merge" {
+				merged, err := yamlNodeToJSON(value, useNumber)
+				if err != nil {
+					return nil, err
+				}
+
+				if m, ok := merged.(map[string]any); ok {
+					for k, v := range m {
+						if _, exists := result[k]; !exists {
+							result[k] = v
+						}
+					}
+				}
+
+				continue
+			}
+
+			val, err := yamlNodeToJSON(value, useNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			result[key.Value] = val
+		}
+
+		return result, nil
+
+	case yaml.SequenceNode:
+		result := make([]any, len(node.Content))
+
+		for i, item := range node.Content {
+			val, err := yamlNodeToJSON(item, useNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = val
+		}
+
+		return result, nil
+
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+
+		case "!!bool":
+			var b bool
+
+			err := node.Decode(&b)
+			if err != nil {
+				return nil, fmt.Errorf("invalid YAML bool %q: %w", node.Value, err)
+			}
+
+			return b, nil
+
+		case "!!int", "!!float":
+			return yamlNumber(node, useNumber)
+
+		default:
+			return node.Value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: kind %d at line %d", ErrUnsupportedYAMLNode, node.Kind, node.Line)
+}
+
+// yamlNumber converts an !!int or !!float scalar to float64, or to json.Number when
+// useNumber is set. Integers written in other bases, e.g. 0x1F, are converted to decimal.
+func yamlNumber(node *yaml.Node, useNumber bool) (any, error) {
+	var f float64
+
+	err := node.Decode(&f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML number %q: %w", node.Value, err)
+	}
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("%w: %s has no JSON representation", ErrUnsupportedYAMLNode, node.Value)
+	}
+
+	if !useNumber {
+		return f, nil
+	}
+
+	if node.ShortTag() == "!!int" {
+		var i big.Int
+
+		err = node.Decode(&i)
+		if err == nil {
+			return json.Number(i.String()), nil
+		}
+	}
+
+	if json.Valid([]byte(node.Value)) {
+		return json.Number(node.Value), nil
+	}
+
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+}
+
+// writeYAMLFile writes data as YAML. When expected is given, matchers and unordered
+// wrappers from the expected file are kept at their paths.
+func writeYAMLFile(path string, data any, expected *ExpectedJSON) error {
+	if expected != nil {
+		data = wrapUnordered(data, "$", expected.UnorderedPaths)
+		data = restoreMatchers(data, "$", expected.ExtractMatcherPositions())
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2) //nolint:mnd // Two-space indentation like the JSON files.
+
+	err := enc.Encode(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	err = enc.Close()
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	// Write matchers unquoted, as they appear in hand-written expected files.
+	out := quotedMatcherRegex.ReplaceAllStringFunc(buf.String(), func(match string) string {
+		return strings.ReplaceAll(match[1:len(match)-1], "''", "'")
+	})
+
+	mkdirErr := os.MkdirAll(filepath.Dir(path), dirPerm)
+	if mkdirErr != nil {
+		return fmt.Errorf("failed to create directory: %w", mkdirErr)
+	}
+
+	writeErr := os.WriteFile(path, []byte(out), filePerm)
+	if writeErr != nil {
+		return fmt.Errorf("failed to write expected file: %w", writeErr)
+	}
+
+	return nil
+}
+
+// restoreMatchers replaces values at matcher positions with their template expressions.
+func restoreMatchers(data any, path string, positions map[string]string) any {
+	if expr, ok := positions[path]; ok {
+		return expr
+	}
+
+	switch v := data.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, val := range v {
+			result[key] = restoreMatchers(val, path+"."+key, positions)
+		}
+
+		return result
+
+	case []any:
+		result := make([]any, len(v))
+		for i, val := range v {
+			result[i] = restoreMatchers(val, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+
+		return result
+
+	default:
+		return v
+	}
+}